@@ -0,0 +1,245 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContactSheetOptions is the options of ContactSheet.
+type ContactSheetOptions struct {
+	Columns        int         // number of thumbnails per row, default is 5
+	ThumbWidth     int         // width of the thumbnails, default is 160
+	ThumbHeight    int         // height of the thumbnails, default is ThumbWidth
+	Gutter         int         // space between and around the thumbnails, default is 10, negative means no gutter
+	Background     color.Color // color of the sheet, default is white
+	Captions       []string    // caption of each image, default is the file name of file sources
+	CaptionOptions TextOptions // font of the captions, which are drawn only if FontPath is set
+	MaxWidth       int         // the sheet is scaled down to fit in MaxWidth x MaxHeight, 0 means no limit
+	MaxHeight      int
+}
+
+// CollageOptions is the options of Collage.
+type CollageOptions struct {
+	MaxWidth   int         // width of the collage, default is 1200
+	MaxHeight  int         // the collage is scaled down to fit in this height, 0 means no limit
+	RowHeight  int         // height the rows are close to, default is 240
+	Gutter     int         // space between the images, default is 6, negative means no gutter
+	Background color.Color // color of the gutters, default is white
+}
+
+// ContactSheet lays out thumbnails of the images in a grid, each with its caption below it.
+// The images can be any source Load accepts, and are not modified.
+func ContactSheet(images []interface{}, options ...ContactSheetOptions) *Image {
+	loaded, err := loadAll(images)
+	if err != nil {
+		i := &Image{}
+		i.addError(err, true)
+		return i
+	}
+
+	var opt ContactSheetOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Columns <= 0 {
+		opt.Columns = 5
+	}
+	if opt.Columns > len(loaded) {
+		opt.Columns = len(loaded)
+	}
+	if opt.ThumbWidth <= 0 {
+		opt.ThumbWidth = 160
+	}
+	if opt.ThumbHeight <= 0 {
+		opt.ThumbHeight = opt.ThumbWidth
+	}
+	if opt.Gutter < 0 {
+		opt.Gutter = 0
+	} else if opt.Gutter == 0 {
+		opt.Gutter = 10
+	}
+	if opt.Background == nil {
+		opt.Background = color.White
+	}
+
+	// the captions take a line below the thumbnails
+	captionHeight := 0
+	captionOptions := opt.CaptionOptions
+	if captionOptions.FontPath != "" {
+		if captionOptions.FontSize <= 0 {
+			captionOptions.FontSize = 12
+		}
+		captionOptions.Align = AlignCenter
+		captionOptions.VerticalAlign = AlignMiddle
+		captionOptions.Wrap = false
+		captionOptions.Ellipsis = true
+		captionOptions.MaxLines = 1
+		_, h, err := MeasureText("Hg", 0, captionOptions)
+		if err != nil {
+			i := &Image{}
+			i.addError(err, true)
+			return i
+		}
+		captionHeight = h + opt.Gutter/2
+	}
+
+	rows := (len(loaded) + opt.Columns - 1) / opt.Columns
+	cellHeight := opt.ThumbHeight + captionHeight
+	i := Canvas(opt.Columns*(opt.ThumbWidth+opt.Gutter)+opt.Gutter, rows*(cellHeight+opt.Gutter)+opt.Gutter, opt.Background)
+
+	for n, img := range loaded {
+		x := opt.Gutter + (n%opt.Columns)*(opt.ThumbWidth+opt.Gutter)
+		y := opt.Gutter + (n/opt.Columns)*(cellHeight+opt.Gutter)
+
+		thumb := img.Clone().fit(opt.ThumbWidth, opt.ThumbHeight, FitContain, GravityCenter)
+		if thumb.Error != nil {
+			i.addError(thumb.Error, true)
+			return i
+		}
+		offset := gravityOffset(GravityCenter, image.Pt(opt.ThumbWidth-thumb.width, opt.ThumbHeight-thumb.height))
+		i.Insert(thumb, x+offset.X, y+offset.Y)
+
+		if captionHeight > 0 {
+			caption := ""
+			if n < len(opt.Captions) {
+				caption = opt.Captions[n]
+			} else {
+				caption = captionOf(images[n])
+			}
+			if caption != "" {
+				i.TextBox(caption, image.Rect(x, y+opt.ThumbHeight, x+opt.ThumbWidth, y+cellHeight), captionOptions)
+			}
+		}
+	}
+
+	return i.fitWithin(opt.MaxWidth, opt.MaxHeight)
+}
+
+// Collage lays out the images in justified rows, like the photo grids of Flickr or Google Photos:
+// the images keep their aspect ratio, and each row is scaled so that it spans the width of the
+// collage, with a height close to RowHeight. The last row keeps RowHeight and is aligned to the left.
+// The images can be any source Load accepts, and are not modified.
+func Collage(images []interface{}, options ...CollageOptions) *Image {
+	loaded, err := loadAll(images)
+	if err != nil {
+		i := &Image{}
+		i.addError(err, true)
+		return i
+	}
+
+	var opt CollageOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.MaxWidth <= 0 {
+		opt.MaxWidth = 1200
+	}
+	if opt.RowHeight <= 0 {
+		opt.RowHeight = 240
+	}
+	if opt.Gutter < 0 {
+		opt.Gutter = 0
+	} else if opt.Gutter == 0 {
+		opt.Gutter = 6
+	}
+	if opt.Background == nil {
+		opt.Background = color.White
+	}
+
+	// the rows, with the range of their images and their height
+	type row struct {
+		start, end int
+		height     int
+		full       bool
+	}
+	var rows []row
+	start, aspects := 0, 0.0
+	for n, img := range loaded {
+		if img.width > 0 && img.height > 0 {
+			aspects += float64(img.width) / float64(img.height)
+		}
+		free := float64(opt.MaxWidth - opt.Gutter*(n-start))
+		if aspects*float64(opt.RowHeight) >= free && aspects > 0 {
+			rows = append(rows, row{start: start, end: n + 1, height: int(math.Max(1, math.Round(free/aspects))), full: true})
+			start, aspects = n+1, 0
+		}
+	}
+	if start < len(loaded) {
+		rows = append(rows, row{start: start, end: len(loaded), height: opt.RowHeight})
+	}
+
+	height := -opt.Gutter
+	for _, r := range rows {
+		height += r.height + opt.Gutter
+	}
+	i := Canvas(opt.MaxWidth, height, opt.Background)
+
+	y := 0
+	for _, r := range rows {
+		x := 0
+		for n := r.start; n < r.end; n++ {
+			img := loaded[n]
+			w := 1
+			if img.height > 0 {
+				w = int(math.Max(1, math.Round(float64(img.width)*float64(r.height)/float64(img.height))))
+			}
+			// the last image of a full row takes the rounding errors, so that the row is justified
+			if (r.full && n == r.end-1) || x+w > opt.MaxWidth {
+				w = opt.MaxWidth - x
+			}
+			if w <= 0 {
+				break
+			}
+
+			cell := img.Clone().fit(w, r.height, FitCover, GravityCenter)
+			if cell.Error != nil {
+				i.addError(cell.Error, true)
+				return i
+			}
+			i.Insert(cell, x, y)
+			x += w + opt.Gutter
+		}
+		y += r.height + opt.Gutter
+	}
+
+	return i.fitWithin(0, opt.MaxHeight)
+}
+
+// fitWithin scales the image down to fit in a width x height box, keeping its aspect ratio.
+// A width or height of 0 means no limit.
+func (i *Image) fitWithin(width, height int) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	scale := 1.0
+	if width > 0 && i.width > width {
+		scale = float64(width) / float64(i.width)
+	}
+	if height > 0 && i.height > height {
+		scale = math.Min(scale, float64(height)/float64(i.height))
+	}
+	if scale == 1 {
+		return i
+	}
+
+	return i.Resize(int(math.Max(1, math.Round(float64(i.width)*scale))), int(math.Max(1, math.Round(float64(i.height)*scale))))
+}
+
+// captionOf returns the file name of a file source, or an empty string.
+func captionOf(source interface{}) string {
+	switch s := source.(type) {
+	case string:
+		if strings.HasPrefix(s, "data:") {
+			return ""
+		}
+		return filepath.Base(s)
+	case *os.File:
+		return filepath.Base(s.Name())
+	}
+	return ""
+}
@@ -7,3 +7,182 @@ const (
 	Vertical FlipType = iota
 	Horizontal
 )
+
+// Text Align
+type TextAlign int
+
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+	AlignJustify
+)
+
+// Vertical Align
+type VerticalAlign int
+
+const (
+	AlignTop VerticalAlign = iota
+	AlignMiddle
+	AlignBottom
+)
+
+// Font Style
+type FontStyle int
+
+const (
+	FontRegular FontStyle = iota
+	FontBold
+	FontItalic
+	FontBoldItalic
+)
+
+// Fill Rule
+type FillRule int
+
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+// Line Cap
+type LineCap int
+
+const (
+	CapButt LineCap = iota
+	CapRound
+	CapSquare
+)
+
+// Line Join
+type LineJoin int
+
+const (
+	JoinMiter LineJoin = iota
+	JoinRound
+	JoinBevel
+)
+
+// Spread Mode
+type SpreadMode int
+
+const (
+	SpreadPad SpreadMode = iota
+	SpreadRepeat
+	SpreadReflect
+)
+
+// Color Interpolation
+type ColorInterpolation int
+
+const (
+	InterpolateSRGB ColorInterpolation = iota
+	InterpolateLinearRGB
+	InterpolateOKLab
+)
+
+// Composite Operation
+type CompositeOp int
+
+const (
+	SourceOver CompositeOp = iota
+	SourceIn
+	SourceOut
+	SourceAtop
+	DestinationOver
+	DestinationIn
+	DestinationOut
+	DestinationAtop
+	Copy
+	Xor
+	Lighter
+	Multiply
+	Screen
+)
+
+// Connectivity
+type Connectivity int
+
+const (
+	Connect4 Connectivity = iota
+	Connect8
+)
+
+// Mask Mode
+type MaskMode int
+
+const (
+	MaskAuto MaskMode = iota
+	MaskAlpha
+	MaskLuminance
+)
+
+// Gravity
+type Gravity int
+
+const (
+	GravityCenter Gravity = iota
+	GravityNorth
+	GravityNorthEast
+	GravityEast
+	GravitySouthEast
+	GravitySouth
+	GravitySouthWest
+	GravityWest
+	GravityNorthWest
+)
+
+// Extend Mode
+type ExtendMode int
+
+const (
+	ExtendColor ExtendMode = iota
+	ExtendEdge
+	ExtendMirror
+	ExtendBlur
+)
+
+// Interpolation
+type Interpolation int
+
+const (
+	InterpolationBilinear Interpolation = iota
+	InterpolationNearest
+	InterpolationBicubic
+)
+
+// Rotate Mode
+type RotateMode int
+
+const (
+	RotateExpand RotateMode = iota
+	RotateKeep
+	RotateCrop
+)
+
+// Tile Layout
+type TileLayout int
+
+const (
+	TileDeepZoom TileLayout = iota
+	TileIIIF
+	TileXYZ
+)
+
+// Direction
+type Direction int
+
+const (
+	DirectionHorizontal Direction = iota
+	DirectionVertical
+)
+
+// Fit
+type Fit int
+
+const (
+	FitContain Fit = iota
+	FitCover
+	FitFill
+	FitNone
+)
@@ -0,0 +1,409 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Context is a 2D drawing context on an image, in the manner of the HTML canvas and Cairo.
+// It keeps a current state, made of a transform, a clip, a global alpha, a composite operation
+// and fill and stroke styles, that can be saved and restored.
+//
+//	ctx := imgo.NewContext(img)
+//	ctx.Save().Translate(100, 100).Rotate(45).FillRect(-20, -20, 40, 40).Restore()
+type Context struct {
+	img   *Image
+	state contextState
+	stack []contextState
+}
+
+// contextState is the drawing state of a context.
+type contextState struct {
+	matrix        Matrix
+	clip          *image.Alpha // coverage of the clip in image coordinates, nil means no clip
+	alpha         float64
+	op            CompositeOp
+	fill          image.Image
+	stroke        image.Image
+	strokeOptions StrokeOptions
+}
+
+// NewContext creates a drawing context on the image.
+func NewContext(img *Image) *Context {
+	return &Context{
+		img: img,
+		state: contextState{
+			matrix: Identity(),
+			alpha:  1,
+			fill:   image.NewUniform(color.Black),
+			stroke: image.NewUniform(color.Black),
+		},
+	}
+}
+
+// Image returns the image the context draws on.
+func (ctx *Context) Image() *Image {
+	return ctx.img
+}
+
+// Save pushes a copy of the current state on the state stack.
+func (ctx *Context) Save() *Context {
+	ctx.stack = append(ctx.stack, ctx.state)
+	return ctx
+}
+
+// Restore pops the last saved state from the state stack and makes it current.
+func (ctx *Context) Restore() *Context {
+	if len(ctx.stack) == 0 {
+		return ctx
+	}
+	ctx.state = ctx.stack[len(ctx.stack)-1]
+	ctx.stack = ctx.stack[:len(ctx.stack)-1]
+	return ctx
+}
+
+// Translate moves the origin of the coordinate system by (x, y).
+func (ctx *Context) Translate(x, y float64) *Context {
+	ctx.state.matrix = ctx.state.matrix.Translate(x, y)
+	return ctx
+}
+
+// Rotate rotates the coordinate system clockwise by angle in degrees.
+func (ctx *Context) Rotate(angle float64) *Context {
+	ctx.state.matrix = ctx.state.matrix.Rotate(angle)
+	return ctx
+}
+
+// Scale scales the coordinate system by (sx, sy).
+func (ctx *Context) Scale(sx, sy float64) *Context {
+	ctx.state.matrix = ctx.state.matrix.Scale(sx, sy)
+	return ctx
+}
+
+// Skew skews the coordinate system by the angles ax and ay in degrees.
+func (ctx *Context) Skew(ax, ay float64) *Context {
+	ctx.state.matrix = ctx.state.matrix.Skew(ax, ay)
+	return ctx
+}
+
+// Transform multiplies the current transform by m.
+func (ctx *Context) Transform(m Matrix) *Context {
+	ctx.state.matrix = ctx.state.matrix.Multiply(m)
+	return ctx
+}
+
+// SetTransform replaces the current transform by m.
+func (ctx *Context) SetTransform(m Matrix) *Context {
+	ctx.state.matrix = m
+	return ctx
+}
+
+// ResetTransform resets the current transform to the identity.
+func (ctx *Context) ResetTransform() *Context {
+	ctx.state.matrix = Identity()
+	return ctx
+}
+
+// Matrix returns the current transform.
+func (ctx *Context) Matrix() Matrix {
+	return ctx.state.matrix
+}
+
+// SetGlobalAlpha sets the opacity applied to everything drawn, between 0 and 1.
+func (ctx *Context) SetGlobalAlpha(alpha float64) *Context {
+	ctx.state.alpha = math.Max(0, math.Min(1, alpha))
+	return ctx
+}
+
+// SetCompositeOperation sets how drawings are combined with the image.
+// Composite operations only affect the pixels covered by a drawing.
+func (ctx *Context) SetCompositeOperation(op CompositeOp) *Context {
+	ctx.state.op = op
+	return ctx
+}
+
+// SetFillColor sets the color shapes are filled with.
+func (ctx *Context) SetFillColor(c color.Color) *Context {
+	return ctx.SetFillSource(image.NewUniform(c))
+}
+
+// SetFillSource sets the image or gradient shapes are filled with, in image coordinates.
+func (ctx *Context) SetFillSource(src image.Image) *Context {
+	ctx.state.fill = src
+	return ctx
+}
+
+// SetStrokeColor sets the color shapes are stroked with.
+func (ctx *Context) SetStrokeColor(c color.Color) *Context {
+	return ctx.SetStrokeSource(image.NewUniform(c))
+}
+
+// SetStrokeSource sets the image or gradient shapes are stroked with, in image coordinates.
+func (ctx *Context) SetStrokeSource(src image.Image) *Context {
+	ctx.state.stroke = src
+	return ctx
+}
+
+// SetStrokeOptions sets the width, caps, joins and dashes of strokes.
+// The stroke width is in user space, so it is scaled by the transform.
+func (ctx *Context) SetStrokeOptions(options StrokeOptions) *Context {
+	ctx.state.strokeOptions = options
+	return ctx
+}
+
+// Clip intersects the current clip with the area inside the path, transformed by the current
+// transform. rule is the fill rule of the path, default is NonZero.
+func (ctx *Context) Clip(path *Path, rule ...FillRule) *Context {
+	if ctx.img.Error != nil {
+		return ctx
+	}
+
+	r := NonZero
+	if len(rule) > 0 {
+		r = rule[0]
+	}
+
+	bounds := ctx.img.image.Bounds()
+	mask := rasterize(path.Transform(ctx.state.matrix).polygons(), r, bounds, true)
+
+	// the new clip is the product of both clips
+	clip := image.NewAlpha(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			a := uint32(alphaAt(mask, x, y))
+			if ctx.state.clip != nil {
+				a = a * uint32(alphaAt(ctx.state.clip, x, y)) / 255
+			}
+			clip.Pix[clip.PixOffset(x, y)] = uint8(a)
+		}
+	}
+	ctx.state.clip = clip
+
+	return ctx
+}
+
+// ResetClip removes the clip.
+func (ctx *Context) ResetClip() *Context {
+	ctx.state.clip = nil
+	return ctx
+}
+
+// FillPath fills the path with the fill color. rule is the fill rule, default is NonZero.
+func (ctx *Context) FillPath(path *Path, rule ...FillRule) *Context {
+	if ctx.img.Error != nil {
+		return ctx
+	}
+
+	r := NonZero
+	if len(rule) > 0 {
+		r = rule[0]
+	}
+
+	polygons := path.Transform(ctx.state.matrix).polygons()
+	ctx.paint(rasterize(polygons, r, ctx.img.image.Bounds(), true), ctx.state.fill)
+
+	return ctx
+}
+
+// StrokePath strokes the path with the stroke color and options.
+func (ctx *Context) StrokePath(path *Path) *Context {
+	if ctx.img.Error != nil {
+		return ctx
+	}
+
+	m := ctx.state.matrix
+	options := ctx.state.strokeOptions
+	if options.Width <= 0 {
+		options.Width = 1
+	}
+
+	// A similarity keeps the stroke shape, so the path is transformed before being flattened
+	// for smoother curves. Other transforms distort the stroke and are applied afterwards.
+	var polygons [][]Point
+	if scale, ok := m.isSimilarity(); ok {
+		options.Width *= scale
+		options.DashOffset *= scale
+		if len(options.Dash) > 0 {
+			dash := make([]float64, len(options.Dash))
+			for n, length := range options.Dash {
+				dash[n] = length * scale
+			}
+			options.Dash = dash
+		}
+		polygons = path.Transform(m).strokePolygons(options)
+	} else {
+		polygons = m.applyPolygons(path.strokePolygons(options))
+	}
+
+	ctx.paint(rasterize(polygons, NonZero, ctx.img.image.Bounds(), !options.Aliased), ctx.state.stroke)
+
+	return ctx
+}
+
+// FillRect fills the rectangle at (x, y) with given width and height with the fill color.
+func (ctx *Context) FillRect(x, y, width, height float64) *Context {
+	return ctx.FillPath(rectPath(x, y, width, height))
+}
+
+// StrokeRect strokes the rectangle at (x, y) with given width and height with the stroke color.
+func (ctx *Context) StrokeRect(x, y, width, height float64) *Context {
+	return ctx.StrokePath(rectPath(x, y, width, height))
+}
+
+// ClearRect makes the rectangle at (x, y) with given width and height transparent.
+func (ctx *Context) ClearRect(x, y, width, height float64) *Context {
+	if ctx.img.Error != nil {
+		return ctx
+	}
+
+	polygons := rectPath(x, y, width, height).Transform(ctx.state.matrix).polygons()
+	mask := rasterize(polygons, NonZero, ctx.img.image.Bounds(), true)
+	ctx.composite(mask, Copy, 1, func(x, y int) [4]float64 { return [4]float64{} })
+
+	return ctx
+}
+
+// DrawImage draws source with its top-left corner at (x, y), transformed by the current transform.
+// source can be a file path, a URL, a base64 encoded string, an *os.File, an image.Image,
+// a byte slice or an *Image.
+func (ctx *Context) DrawImage(source interface{}, x, y float64) *Context {
+	var src *Image
+	switch source.(type) {
+	case *Image:
+		src = source.(*Image)
+	default:
+		src = Load(source)
+	}
+
+	if src.Error != nil {
+		ctx.img.addError(src.Error, true)
+		return ctx
+	}
+	if ctx.img.Error != nil {
+		return ctx
+	}
+
+	// the image is placed in user space, and each pixel of the destination is mapped back to it
+	m := ctx.state.matrix.Translate(x, y)
+	inverse, ok := m.Invert()
+	if !ok {
+		return ctx
+	}
+
+	// the edges are already smoothed by the bilinear sampling, so the mask needs no anti-aliasing
+	w, h := float64(src.width), float64(src.height)
+	outline := [][]Point{{Pt(0, 0), Pt(w, 0), Pt(w, h), Pt(0, h)}}
+	mask := rasterize(m.applyPolygons(outline), NonZero, ctx.img.image.Bounds(), false)
+
+	ctx.composite(mask, ctx.state.op, ctx.state.alpha, func(px, py int) [4]float64 {
+		p := inverse.Apply(Pt(float64(px)+0.5, float64(py)+0.5))
+		return sampleBilinear(src.image, p.X, p.Y)
+	})
+
+	return ctx
+}
+
+// paint composites src through the coverage mask with the current state.
+func (ctx *Context) paint(mask *image.Alpha, src image.Image) {
+	ctx.composite(mask, ctx.state.op, ctx.state.alpha, func(x, y int) [4]float64 {
+		r, g, b, a := src.At(x, y).RGBA()
+		return [4]float64{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff, float64(a) / 0xffff}
+	})
+}
+
+// composite combines the source colors with the image through the coverage mask and the clip,
+// with the composite operation. source returns premultiplied colors with components between 0 and 1.
+func (ctx *Context) composite(mask *image.Alpha, op CompositeOp, alpha float64, source func(x, y int) [4]float64) {
+	dst := ctx.img.image
+	bounds := mask.Bounds().Intersect(dst.Bounds())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			coverage := float64(mask.Pix[mask.PixOffset(x, y)]) / 255
+			if ctx.state.clip != nil {
+				coverage *= float64(alphaAt(ctx.state.clip, x, y)) / 255
+			}
+			if coverage == 0 {
+				continue
+			}
+
+			s := source(x, y)
+			for c := range s {
+				s[c] *= alpha
+			}
+			p := dst.Pix[dst.PixOffset(x, y):]
+			d := [4]float64{float64(p[0]) / 255, float64(p[1]) / 255, float64(p[2]) / 255, float64(p[3]) / 255}
+			o := compositePixel(op, s, d)
+
+			// outside of the drawing, or where it is partially covered, the image shows through
+			for c := 0; c < 4; c++ {
+				v := d[c] + (o[c]-d[c])*coverage
+				p[c] = uint8(math.Max(0, math.Min(1, v))*255 + 0.5)
+			}
+		}
+	}
+}
+
+// compositePixel combines the premultiplied source and destination colors s and d with op.
+func compositePixel(op CompositeOp, s, d [4]float64) (o [4]float64) {
+	as, ad := s[3], d[3]
+
+	// Porter-Duff operators as the factors of the source and the destination
+	var fs, fd float64
+	switch op {
+	case SourceIn:
+		fs, fd = ad, 0
+	case SourceOut:
+		fs, fd = 1-ad, 0
+	case SourceAtop:
+		fs, fd = ad, 1-as
+	case DestinationOver:
+		fs, fd = 1-ad, 1
+	case DestinationIn:
+		fs, fd = 0, as
+	case DestinationOut:
+		fs, fd = 0, 1-as
+	case DestinationAtop:
+		fs, fd = 1-ad, as
+	case Copy:
+		fs, fd = 1, 0
+	case Xor:
+		fs, fd = 1-ad, 1-as
+	case Lighter:
+		fs, fd = 1, 1
+	case Multiply, Screen:
+		// blend modes, source over with the blended color where both overlap
+		for c := 0; c < 3; c++ {
+			blended := s[c] * d[c]
+			if op == Screen {
+				blended = s[c] + d[c] - s[c]*d[c]
+				o[c] = blended
+				continue
+			}
+			o[c] = blended + s[c]*(1-ad) + d[c]*(1-as)
+		}
+		o[3] = as + ad - as*ad
+		return
+	default: // SourceOver
+		fs, fd = 1, 1-as
+	}
+
+	for c := range o {
+		o[c] = math.Min(1, s[c]*fs+d[c]*fd)
+	}
+	return
+}
+
+// rectPath returns the path of the rectangle at (x, y) with given width and height.
+func rectPath(x, y, width, height float64) *Path {
+	return NewPath().MoveTo(x, y).LineTo(x+width, y).LineTo(x+width, y+height).LineTo(x, y+height).Close()
+}
+
+// alphaAt returns the alpha of mask at (x, y), or 0 outside of its bounds.
+func alphaAt(mask *image.Alpha, x, y int) uint8 {
+	if !image.Pt(x, y).In(mask.Bounds()) {
+		return 0
+	}
+	return mask.Pix[mask.PixOffset(x, y)]
+}
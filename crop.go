@@ -0,0 +1,83 @@
+package imgo
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// CropGravity cuts out a part of the image with given width and height, placed according to gravity,
+// for example GravityCenter keeps the center of the image. Like Crop, an empty size adds a *CropError.
+func (i *Image) CropGravity(width, height int, gravity Gravity) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if width > i.width {
+		width = i.width
+	}
+	if height > i.height {
+		height = i.height
+	}
+
+	offset := gravityOffset(gravity, image.Pt(i.width-width, i.height-height))
+	return i.cropRect(image.Rect(offset.X, offset.Y, offset.X+width, offset.Y+height))
+}
+
+// CropPercent cuts out a part of the image, with its position and size given in percentages
+// of the image size, between 0 and 100. Like Crop, an empty region adds a *CropError.
+func (i *Image) CropPercent(x, y, width, height float64) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	w, h := float64(i.width)/100, float64(i.height)/100
+	x0, y0 := int(math.Round(x*w)), int(math.Round(y*h))
+	x1, y1 := int(math.Round((x+width)*w)), int(math.Round((y+height)*h))
+	return i.cropRect(image.Rectangle{Min: image.Pt(x0, y0), Max: image.Pt(x1, y1)})
+}
+
+// CropAspect cuts out the largest part of the image with the aspect ratio width:height,
+// for example CropAspect(16, 9) for a 16:9 region. gravity places the region, default is GravityCenter.
+func (i *Image) CropAspect(width, height float64, gravity ...Gravity) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if width <= 0 || height <= 0 {
+		return i
+	}
+
+	g := GravityCenter
+	if len(gravity) > 0 {
+		g = gravity[0]
+	}
+
+	w, h := i.width, int(math.Round(float64(i.width)*height/width))
+	if h > i.height {
+		w, h = int(math.Round(float64(i.height)*width/height)), i.height
+	}
+	return i.CropGravity(w, h, g)
+}
+
+// cropRect cuts out rect, in coordinates relative to the top-left corner of the image, clamped to the image.
+// An empty rect, including one with a negative size, is an error.
+func (i *Image) cropRect(rect image.Rectangle) *Image {
+	bounds := i.image.Bounds()
+	region := rect.Add(bounds.Min).Intersect(bounds)
+	if region.Empty() {
+		i.addError(&CropError{Rect: rect, Bounds: bounds.Sub(bounds.Min)})
+		return i
+	}
+	if region == bounds {
+		return i
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+	draw.Draw(dst, dst.Bounds(), i.image, region.Min, draw.Src)
+	i.image = dst
+	i.width = region.Dx()
+	i.height = region.Dy()
+
+	return i
+}
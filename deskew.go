@@ -0,0 +1,230 @@
+package imgo
+
+import (
+	"github.com/nfnt/resize"
+	"image"
+	"image/color"
+	"math"
+)
+
+// the size of the longest side of the copies deskew and document detection work on
+const (
+	deskewSize   = 1000
+	documentSize = 400
+)
+
+// DeskewOptions is the options of Deskew.
+type DeskewOptions struct {
+	MaxAngle   float64     // largest skew detected, in degrees, default is 15
+	Background color.Color // color of the corners uncovered by the rotation, default is transparent
+	Mode       RotateMode  // size of the result, default is RotateExpand
+}
+
+// Deskew straightens a scanned or photographed document, rotating it so that its text lines are horizontal.
+func (i *Image) Deskew(options ...DeskewOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	var opt DeskewOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	angle := i.SkewAngle(opt.MaxAngle)
+	if math.Abs(angle) < 0.05 {
+		return i
+	}
+
+	return i.RotateAngle(-angle, RotateOptions{
+		Interpolation: InterpolationBicubic,
+		Background:    opt.Background,
+		Mode:          opt.Mode,
+	})
+}
+
+// SkewAngle returns the angle in degrees of the text lines of a document, clockwise, between
+// -maxAngle and maxAngle, default is 15. Rotating the image by the opposite angle straightens it.
+// The angle is the one with the sharpest horizontal projection profile of the dark pixels.
+func (i Image) SkewAngle(maxAngle ...float64) float64 {
+	if i.Error != nil || i.width == 0 || i.height == 0 {
+		return 0
+	}
+
+	limit := 15.0
+	if len(maxAngle) > 0 && maxAngle[0] > 0 {
+		limit = maxAngle[0]
+	}
+
+	gray, w, _ := grayscaleOf(i.image, deskewSize)
+	threshold := otsuThreshold(gray)
+
+	// the coordinates of the dark pixels
+	var xs, ys []float64
+	for n, v := range gray {
+		if v < threshold {
+			xs = append(xs, float64(n%w))
+			ys = append(ys, float64(n/w))
+		}
+	}
+	if len(xs) == 0 {
+		return 0
+	}
+
+	// the sharpness of the profile, the sum of the squared differences between neighbouring rows
+	score := func(angle float64) float64 {
+		sin, cos := math.Sincos(angle * math.Pi / 180)
+		offset := math.Max(0, float64(w)*sin)
+		bins := make([]float64, int(math.Ceil(float64(len(gray)/w)*cos+float64(w)*math.Abs(sin)))+2)
+		for n := range xs {
+			y := ys[n]*cos - xs[n]*sin + offset
+			bins[int(math.Max(0, math.Min(float64(len(bins)-1), y)))]++
+		}
+		s := 0.0
+		for n := 1; n < len(bins); n++ {
+			d := bins[n] - bins[n-1]
+			s += d * d
+		}
+		return s
+	}
+
+	// a coarse search, refined around the best angle
+	best, bestScore := 0.0, score(0)
+	for step, span := 0.5, limit; step >= 0.05; step, span = step/2, step {
+		center := best
+		for angle := center - span; angle <= center+span+1e-9; angle += step {
+			if math.Abs(angle) > limit {
+				continue
+			}
+			if s := score(angle); s > bestScore {
+				best, bestScore = angle, s
+			}
+		}
+	}
+
+	return best
+}
+
+// DetectDocumentQuad returns the corners of a document photographed on a darker background,
+// such as a receipt on a table, which can be passed to Perspective to straighten it.
+// It assumes the document is brighter than its background: the document is the largest connected
+// region of pixels brighter than the Otsu threshold, so a document on a background as bright as
+// itself, or darker than it, is not found. It returns false if no document is found.
+func (i Image) DetectDocumentQuad() (Quad, bool) {
+	if i.Error != nil || i.width == 0 || i.height == 0 {
+		return Quad{}, false
+	}
+
+	gray, w, scale := grayscaleOf(i.image, documentSize)
+	h := len(gray) / w
+	threshold := otsuThreshold(gray)
+
+	// the largest connected region of bright pixels is the document
+	visited := make([]bool, len(gray))
+	var best []int
+	for start := range gray {
+		if visited[start] || gray[start] < threshold {
+			continue
+		}
+		visited[start] = true
+		region := []int{start}
+		for k := 0; k < len(region); k++ {
+			n := region[k]
+			x, y := n%w, n/w
+			for _, m := range [4]int{n - 1, n + 1, n - w, n + w} {
+				if (m == n-1 && x == 0) || (m == n+1 && x == w-1) || (m == n-w && y == 0) || (m == n+w && y == h-1) {
+					continue
+				}
+				if !visited[m] && gray[m] >= threshold {
+					visited[m] = true
+					region = append(region, m)
+				}
+			}
+		}
+		if len(region) > len(best) {
+			best = region
+		}
+	}
+	if len(best) < len(gray)/10 {
+		return Quad{}, false
+	}
+
+	// the corners are the extreme points along the diagonals
+	var quad Quad
+	var extremes [4]float64
+	for k := range extremes {
+		extremes[k] = math.Inf(-1)
+	}
+	for _, n := range best {
+		x, y := float64(n%w)+0.5, float64(n/w)+0.5
+		for k, v := range [4]float64{-x - y, x - y, x + y, y - x} {
+			if v > extremes[k] {
+				extremes[k] = v
+				quad[k] = Pt(x, y)
+			}
+		}
+	}
+	for k := range quad {
+		quad[k] = quad[k].Mul(1 / scale)
+	}
+
+	return quad, true
+}
+
+// grayscaleOf returns the luminance of img, scaled down so that its longest side is at most size,
+// with the width and the scale of the copy.
+func grayscaleOf(img *image.RGBA, size int) (gray []uint8, width int, scale float64) {
+	b := img.Bounds()
+	scale = math.Min(1, float64(size)/math.Max(float64(b.Dx()), float64(b.Dy())))
+	src := img
+	if scale < 1 {
+		w := uint(math.Max(1, math.Round(float64(b.Dx())*scale)))
+		h := uint(math.Max(1, math.Round(float64(b.Dy())*scale)))
+		src = Image2RGBA(resize.Resize(w, h, img, resize.Bilinear))
+		scale = float64(w) / float64(b.Dx())
+	}
+
+	sb := src.Bounds()
+	gray = make([]uint8, sb.Dx()*sb.Dy())
+	for y := sb.Min.Y; y < sb.Max.Y; y++ {
+		for x := sb.Min.X; x < sb.Max.X; x++ {
+			p := src.Pix[src.PixOffset(x, y):]
+			// transparent pixels are white, like paper
+			l := (19595*uint32(p[0]) + 38470*uint32(p[1]) + 7471*uint32(p[2]) + 1<<15) >> 16
+			gray[(y-sb.Min.Y)*sb.Dx()+x-sb.Min.X] = uint8(l + 255 - uint32(p[3]))
+		}
+	}
+	return gray, sb.Dx(), scale
+}
+
+// otsuThreshold returns the threshold that best separates the values into dark and bright ones.
+func otsuThreshold(values []uint8) uint8 {
+	var histogram [256]float64
+	for _, v := range values {
+		histogram[v]++
+	}
+
+	total, sum := float64(len(values)), 0.0
+	for v, count := range histogram {
+		sum += float64(v) * count
+	}
+
+	var best uint8
+	var bestVariance, weight, sumBelow float64
+	for v := 0; v < 256; v++ {
+		weight += histogram[v]
+		if weight == 0 || weight == total {
+			continue
+		}
+		sumBelow += float64(v) * histogram[v]
+		meanBelow := sumBelow / weight
+		meanAbove := (sum - sumBelow) / (total - weight)
+		variance := weight * (total - weight) * (meanBelow - meanAbove) * (meanBelow - meanAbove)
+		if variance > bestVariance {
+			best, bestVariance = uint8(v), variance
+		}
+	}
+
+	// the threshold is the first bright value
+	return best + 1
+}
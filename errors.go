@@ -1,6 +1,10 @@
 package imgo
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"image"
+)
 
 var (
 	ErrSourceImageIsNil          = errors.New("source image is nil")
@@ -8,4 +12,51 @@ var (
 	ErrSourceStringIsEmpty       = errors.New("source string is empty")
 	ErrSourceImageNotSupport     = errors.New("source image not support")
 	ErrSaveImageFormatNotSupport = errors.New("save image format not support")
+	ErrFontSourceNotSupport      = errors.New("font source not support")
+	ErrInvalidSVGPath            = errors.New("invalid svg path")
+	ErrNoImages                  = errors.New("no images")
+	ErrMatrixNotInvertible       = errors.New("matrix not invertible")
+	ErrDegenerateQuad            = errors.New("degenerate quad")
+	ErrEmptyTransform            = errors.New("transformed image is empty")
+	ErrTileIDRequired            = errors.New("tile id required for iiif")
+	ErrInvalidStarPoints         = errors.New("star needs at least 2 points")
 )
+
+// CropError is the error of a crop region that is empty or doesn't intersect the image.
+type CropError struct {
+	Rect   image.Rectangle // the region to crop
+	Bounds image.Rectangle // the bounds of the image
+}
+
+func (e *CropError) Error() string {
+	return fmt.Sprintf("crop region %v does not intersect the image bounds %v", e.Rect, e.Bounds)
+}
+
+// imgoError is an error of an image, with a message formatted for the log.
+// It keeps the errors it is made of, so that they can be checked with errors.Is and errors.As.
+type imgoError struct {
+	msg  string
+	errs []error
+}
+
+func (e *imgoError) Error() string {
+	return e.msg
+}
+
+func (e *imgoError) Is(target error) bool {
+	for _, err := range e.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *imgoError) As(target interface{}) bool {
+	for _, err := range e.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	gopher := imgo.Load("gopher.png")
+	wide := gopher.Clone().Resize(400, 200)
+	tall := gopher.Clone().Resize(100, 300)
+
+	// thumbnails with their file names
+	imgo.ContactSheet([]interface{}{"gopher.png", "gopher.png", wide, tall, gopher}, imgo.ContactSheetOptions{
+		Columns:        3,
+		ThumbWidth:     120,
+		Captions:       []string{"gopher.png", "copy.png", "wide.png", "tall.png"},
+		CaptionOptions: imgo.TextOptions{FontPath: "Roboto-Regular.ttf", FontSize: 12},
+	}).Save("contact.png")
+
+	// justified rows, like a photo gallery
+	imgo.Collage([]interface{}{gopher, wide, tall, gopher, wide, gopher, tall, wide}, imgo.CollageOptions{
+		MaxWidth:   800,
+		RowHeight:  180,
+		Gutter:     4,
+		Background: color.Black,
+	}).Save("collage.png")
+}
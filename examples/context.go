@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+func main() {
+	img := imgo.Canvas(400, 300, color.White)
+	ctx := imgo.NewContext(img)
+
+	// a rotated square with a dashed outline
+	ctx.Save().
+		Translate(100, 100).
+		Rotate(30).
+		SetFillColor(colornames.Tomato).
+		FillRect(-40, -40, 80, 80).
+		SetStrokeOptions(imgo.StrokeOptions{Width: 3, Dash: []float64{6, 3}}).
+		StrokeRect(-40, -40, 80, 80).
+		Restore()
+
+	// an image clipped to a circle
+	circle := imgo.NewPath().
+		MoveTo(220, 150).
+		ArcTo(80, 80, 0, true, true, 380, 150).
+		ArcTo(80, 80, 0, true, true, 220, 150).
+		Close()
+	ctx.Save().
+		Clip(circle).
+		SetGlobalAlpha(0.8).
+		DrawImage("gopher.png", 200, 50).
+		Restore()
+
+	// multiply a band over everything
+	ctx.SetCompositeOperation(imgo.Multiply).
+		SetFillColor(colornames.Gold).
+		FillRect(0, 240, 400, 40)
+
+	img.Save("out.png")
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"github.com/fishtailstudio/imgo"
+	"log"
+)
+
+func main() {
+	// keep the bottom-right corner
+	imgo.Load("gopher.png").
+		CropGravity(100, 100, imgo.GravitySouthEast).
+		Save("corner.png")
+
+	// the central half of the image
+	imgo.Load("gopher.png").
+		CropPercent(25, 25, 50, 50).
+		Save("center.png")
+
+	// 16:9 centered
+	imgo.Load("gopher.png").
+		CropAspect(16, 9).
+		Save("wide.png")
+
+	// a region outside of the image is an error
+	img := imgo.Load("gopher.png").Crop(1000, 1000, 100, 100)
+	var cropErr *imgo.CropError
+	if errors.As(img.Error, &cropErr) {
+		log.Println("nothing to crop in", cropErr.Bounds)
+	}
+}
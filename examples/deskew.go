@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	// straighten a tilted scan
+	scan := imgo.Load("scan.jpg")
+	fmt.Printf("skew: %.2f degrees\n", scan.SkewAngle())
+	scan.Deskew(imgo.DeskewOptions{Background: color.White, Mode: imgo.RotateCrop}).
+		Save("scan-straight.jpg")
+
+	// find a receipt on a table and flatten it
+	photo := imgo.Load("receipt.jpg")
+	if quad, ok := photo.DetectDocumentQuad(); ok {
+		photo.Perspective(quad, imgo.RectQuad(0, 0, 600, 1200)).
+			Save("receipt-flat.jpg")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	// add a white margin, larger at the bottom
+	imgo.Load("gopher.png").
+		Extend(20, 20, 60, 20, imgo.Background{Color: color.White}).
+		Save("margin.png")
+
+	// mirror the image around itself
+	imgo.Load("gopher.png").
+		Extend(50, 50, 50, 50, imgo.Background{Mode: imgo.ExtendMirror}).
+		Save("mirror.png")
+
+	// square letterbox with a blurred copy as background
+	imgo.Load("gopher.png").
+		PadTo(400, 400, imgo.GravityCenter, imgo.Background{Mode: imgo.ExtendBlur, Blur: 20}).
+		Save("letterbox.png")
+}
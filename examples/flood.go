@@ -0,0 +1,25 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+func main() {
+	img := imgo.Load("gopher.png")
+
+	// recolor the region around (100, 100)
+	img.Clone().
+		FloodFill(100, 100, colornames.Orange, 30, imgo.Connect8).
+		Save("out.png")
+
+	// remove the background connected to the top-left corner
+	img.Clone().
+		FloodFill(0, 0, color.Transparent, 10).
+		Save("transparent.png")
+
+	// select a region as a mask
+	img.SelectRegion(100, 100, 30).
+		Save("mask.png")
+}
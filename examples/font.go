@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	// fonts are parsed once, and their faces are cached per size and DPI
+	imgo.RegisterFont("Roboto", imgo.FontRegular, "Roboto-Regular.ttf")
+	imgo.RegisterFont("Roboto", imgo.FontBold, "Roboto-Bold.ttf")
+	imgo.SetFontFallback("Roboto", "NotoSansCJK-Regular.otf")
+
+	imgo.Canvas(500, 200, color.White).
+		Text("Hello, 世界", 20, 40, "Roboto", color.Black, 32, 72).
+		Text("Bold label", 20, 100, "Roboto Bold", color.Black, 32, 72).
+		Save("out.png")
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image"
+	"image/color"
+)
+
+func main() {
+	background := imgo.NewLinearGradient(0, 0, 500, 500).
+		AddStop(0, colornames.Midnightblue).
+		AddStop(1, colornames.Mediumpurple).
+		SetInterpolation(imgo.InterpolateOKLab)
+
+	// fade the bottom of the image to black, behind a caption
+	fade := imgo.NewLinearGradient(0, 350, 0, 500).
+		AddStop(0, color.Transparent).
+		AddStop(1, color.Alpha{A: 220})
+
+	sun := imgo.NewRadialGradient(220, 170, 120).
+		AddStop(0, color.White).
+		AddStop(1, colornames.Gold)
+
+	imgo.Canvas(500, 500).
+		PaintRect(image.Rect(0, 0, 500, 500), background).
+		Circle(250, 200, 120, nil, imgo.ShapeStyle{Fill: sun}).
+		FillRect(image.Rect(0, 350, 500, 500), color.Black, fade).
+		Save("out.png")
+}
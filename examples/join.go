@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	before := imgo.Load("gopher.png")
+	after := imgo.Load("gopher.png").Clone().Flip(imgo.Horizontal)
+
+	// before and after, side by side
+	imgo.Append([]interface{}{before, after}, imgo.DirectionHorizontal, 10, imgo.GravityCenter, color.White).
+		Save("compare.png")
+
+	// stacked, with a smaller image aligned to the right
+	small := imgo.Load("gopher.png").Clone().Resize(95, 128)
+	imgo.Append([]interface{}{before, small}, imgo.DirectionVertical, 0, imgo.GravityEast).
+		Save("stack.png")
+
+	// a strip of square thumbnails
+	imgo.Grid([]interface{}{before, after, small, "gopher.png"}, 4, 120, 120, imgo.FitCover, imgo.GridOptions{
+		Spacing:    5,
+		Background: color.White,
+	}).Save("strip.png")
+
+	// 2 columns, letterboxed in the cells
+	imgo.Grid([]interface{}{before, after, small}, 2, 150, 100, imgo.FitContain, imgo.GridOptions{
+		Spacing:    4,
+		Background: color.Black,
+	}).Save("grid.png")
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+func main() {
+	points := []imgo.Point{imgo.Pt(50, 400), imgo.Pt(150, 250), imgo.Pt(250, 300), imgo.Pt(350, 100), imgo.Pt(450, 150)}
+
+	imgo.Canvas(500, 500, color.White).
+		Line(50, 450, 450, 450, color.Black, 2).
+		Polyline(points, colornames.Steelblue, imgo.StrokeOptions{
+			Width: 6,
+			Cap:   imgo.CapRound,
+			Join:  imgo.JoinRound,
+		}).
+		Polyline(points, colornames.Tomato, imgo.StrokeOptions{
+			Width: 2,
+			Dash:  []float64{10, 6},
+		}).
+		Save("out.png")
+}
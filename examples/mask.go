@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	// round avatar
+	imgo.Load("gopher.png").
+		CircleMask().
+		Save("circle.png")
+
+	// star shaped avatar
+	imgo.Load("gopher.png").
+		StarMask(5, 0.4).
+		Save("star.png")
+
+	// any grayscale or alpha mask
+	imgo.Load("gopher.png").
+		Mask("mask.png").
+		Save("masked.png")
+}
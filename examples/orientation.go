@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	imgo.Load("gopher.png").
+		Rotate90().
+		Save("rotate90.png")
+
+	imgo.Load("gopher.png").
+		Transpose().
+		Save("transpose.png")
+
+	imgo.Load("gopher.png").
+		Transverse().
+		Save("transverse.png")
+}
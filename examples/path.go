@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image/color"
+	"log"
+)
+
+func main() {
+	heart, err := imgo.ParseSVGPath("M 100,160 Q 20,110 20,70 A 40,40 0,0,1 100,50 A 40,40 0,0,1 180,70 Q 180,110 100,160 Z")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	badge := imgo.NewPath().
+		MoveTo(250, 40).
+		LineTo(350, 40).
+		CubicTo(380, 40, 380, 100, 350, 100).
+		LineTo(250, 100).
+		Close()
+
+	imgo.Canvas(400, 200, color.White).
+		FillPath(heart, colornames.Crimson).
+		StrokePath(heart, color.Black, imgo.StrokeOptions{Width: 3, Join: imgo.JoinRound}).
+		FillPath(badge, colornames.Seagreen, imgo.EvenOdd).
+		Save("out.png")
+}
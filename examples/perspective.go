@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	// straighten a photographed document to an A4 page
+	imgo.Load("document.jpg").
+		Perspective(
+			imgo.Quad{{X: 112, Y: 80}, {X: 890, Y: 130}, {X: 940, Y: 1210}, {X: 60, Y: 1180}},
+			imgo.RectQuad(0, 0, 827, 1169),
+			imgo.TransformOptions{Interpolation: imgo.InterpolationBicubic},
+		).
+		Save("page.jpg")
+
+	// map artwork onto the screen of a mockup
+	art := imgo.Load("gopher.png")
+	screen := imgo.Quad{{X: 420, Y: 210}, {X: 760, Y: 250}, {X: 750, Y: 560}, {X: 410, Y: 540}}
+	art.Perspective(imgo.RectQuad(0, 0, float64(art.Width()), float64(art.Height())), screen)
+	imgo.Load("mockup.jpg").
+		Insert(art, 0, 0).
+		Save("mockup-out.jpg")
+
+	// shear with an affine transform
+	imgo.Load("gopher.png").
+		Affine(imgo.Identity().Translate(100, 0).Skew(-20, 0)).
+		Save("sheared.png")
+}
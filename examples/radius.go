@@ -2,11 +2,22 @@ package main
 
 import (
 	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
 	"image/color"
 )
 
 func main() {
-	imgo.Canvas(300, 300, color.White).
+	imgo.Canvas(300, 300, colornames.Steelblue).
 		BorderRadius(20).
 		Save("out.png")
+
+	// different radii per corner, with a border, on a white background for JPEG
+	imgo.Canvas(300, 300, colornames.Steelblue).
+		BorderRadius(0, imgo.BorderRadiusOptions{
+			Radii:       imgo.CornerRadii{TopLeft: 80, TopRight: 20, BottomRight: 80, BottomLeft: 20},
+			BorderWidth: 4,
+			BorderColor: colornames.Navy,
+			Background:  color.White,
+		}).
+		Save("out.jpg")
 }
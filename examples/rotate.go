@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	// straighten a photo tilted by 3.5 degrees, without transparent corners
+	imgo.Load("gopher.png").
+		RotateAngle(-3.5, imgo.RotateOptions{Mode: imgo.RotateCrop, Interpolation: imgo.InterpolationBicubic}).
+		Save("straight.png")
+
+	// rotate on a white background
+	imgo.Load("gopher.png").
+		RotateAngle(30, imgo.RotateOptions{Background: color.White}).
+		Save("rotated.jpg")
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	// narrower without squeezing the content
+	imgo.Load("gopher.png").
+		SeamCarve(150, 256).
+		Save("narrow.png")
+
+	// wider, keeping the face undistorted
+	face := imgo.Canvas(189, 256, color.Transparent).
+		Ellipse(95, 70, 150, 110, color.White)
+	imgo.Load("gopher.png").
+		SeamCarve(280, 256, imgo.SeamCarveOptions{Protect: face}).
+		Save("wide.png")
+}
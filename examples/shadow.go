@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"golang.org/x/image/colornames"
+	"image/color"
+)
+
+func main() {
+	imgo.Load("gopher.png").
+		OuterGlow(20, colornames.Gold, 1).
+		Save("glow.png")
+
+	imgo.Load("gopher.png").
+		BorderRadius(30).
+		InnerGlow(20, color.White, 0.8).
+		DropShadow(10, 10, 8, color.Black, 0.5).
+		Save("shadow.png")
+
+	imgo.Load("gopher.png").
+		Polaroid(imgo.PolaroidOptions{
+			Caption:        "Gopher",
+			CaptionOptions: imgo.TextOptions{FontPath: "Roboto-Regular.ttf", FontSize: 18, DPI: 72},
+		}).
+		Border(1, colornames.Lightgray).
+		Save("polaroid.png")
+}
@@ -13,5 +13,7 @@ func main() {
 		Circle(200, 100, 50, colornames.Aqua).
 		Rectangle(150, 200, 100, 150, colornames.Darkblue).
 		Ellipse(400, 200, 150, 50, colornames.Tomato).
+		Circle(400, 400, 60, nil, imgo.ShapeStyle{Stroke: colornames.White, StrokeWidth: 4}).
+		RoundedRectangle(50, 400, 200, 60, imgo.UniformRadii(12), colornames.Seagreen, imgo.ShapeStyle{Opacity: 0.8}).
 		Save("out.png")
 }
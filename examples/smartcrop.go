@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	img := imgo.Load("gopher.png")
+
+	// the region chosen for a square thumbnail
+	fmt.Println(img.SmartCropRect(100, 100))
+
+	img.SmartCrop(100, 100).
+		Save("thumbnail.png")
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image"
+	"image/color"
+)
+
+func main() {
+	imgo.Canvas(600, 315, color.White).
+		TextBox("A dynamic title that is too long to fit on a single line of the share card", image.Rect(40, 40, 560, 275), imgo.TextOptions{
+			FontPath:      "font.ttf",
+			FontSize:      36,
+			Color:         color.Black,
+			Align:         imgo.AlignCenter,
+			VerticalAlign: imgo.AlignMiddle,
+			LineHeight:    1.2,
+			Wrap:          true,
+			Ellipsis:      true,
+		}).
+		Save("out.png")
+}
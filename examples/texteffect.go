@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image"
+	"image/color"
+)
+
+func main() {
+	imgo.Load("gopher.png").
+		TextBox("TOP TEXT", image.Rect(0, 10, 189, 60), imgo.TextOptions{
+			FontPath:    "font.ttf",
+			FontSize:    32,
+			Color:       color.White,
+			Align:       imgo.AlignCenter,
+			StrokeWidth: 3,
+			StrokeColor: color.Black,
+			Shadow:      &imgo.Shadow{OffsetX: 3, OffsetY: 3, Blur: 2},
+		}).
+		TextBox("NEW", image.Rect(10, 220, 189, 250), imgo.TextOptions{
+			FontPath:      "font.ttf",
+			FontSize:      16,
+			Color:         color.White,
+			LetterSpacing: 2,
+			Background:    &imgo.TextBackground{Color: color.RGBA{R: 220, A: 255}, Padding: 6, Radius: 8},
+		}).
+		Save("out.png")
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	// Deep Zoom pyramid for OpenSeadragon, with 1 pixel of overlap
+	imgo.Load("gopher.png").SaveTiles("dzi", imgo.TileOptions{
+		TileSize: 64,
+		Overlap:  1,
+	})
+
+	// static IIIF image service
+	imgo.Load("gopher.png").SaveTiles("iiif", imgo.TileOptions{
+		Layout:   imgo.TileIIIF,
+		TileSize: 64,
+		ID:       "https://example.com/iiif/gopher",
+	})
+
+	// XYZ tiles for map viewers such as Leaflet
+	imgo.Load("gopher.png").SaveTiles("xyz", imgo.TileOptions{
+		Layout:   imgo.TileXYZ,
+		TileSize: 64,
+		Format:   "png",
+	})
+
+	// split into a grid of tiles
+	for y, row := range imgo.Load("gopher.png").Tiles(100, 100) {
+		for x, tile := range row {
+			tile.Save(fmt.Sprintf("tile_%d_%d.png", x, y))
+		}
+	}
+}
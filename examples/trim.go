@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"github.com/fishtailstudio/imgo"
+)
+
+func main() {
+	img := imgo.Load("scan.jpg")
+
+	// only detect the content
+	fmt.Println(img.TrimRect(10))
+
+	// crop the white margins, keeping 8 pixels around the content
+	img.Trim(10, imgo.TrimOptions{Padding: 8}).
+		Save("out.jpg")
+}
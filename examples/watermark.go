@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/fishtailstudio/imgo"
+	"image/color"
+)
+
+func main() {
+	imgo.Canvas(500, 500, color.White).
+		Watermark("gopher.png", imgo.WatermarkOptions{
+			SpacingX: 20,
+			SpacingY: 20,
+			Angle:    30,
+			Opacity:  0.3,
+			Stagger:  true,
+		}).
+		Save("out.png")
+}
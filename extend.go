@@ -0,0 +1,201 @@
+package imgo
+
+import (
+	"github.com/BurntSushi/graphics-go/graphics"
+	"github.com/nfnt/resize"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Background is how the area added around an image is filled.
+type Background struct {
+	Mode  ExtendMode  // how the area is filled, default is ExtendColor
+	Color color.Color // color of ExtendColor, default is transparent
+	Blur  float64     // standard deviation of the blur of ExtendBlur, default is 2% of the largest side
+}
+
+// Extend grows the image by the given sizes on each side, filled with background:
+// a solid color, the replicated edge pixels, the mirrored image, or a blurred copy of the
+// image stretched to the new size, as in letterboxed photos.
+func (i *Image) Extend(top, right, bottom, left int, background Background) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if top < 0 {
+		top = 0
+	}
+	if right < 0 {
+		right = 0
+	}
+	if bottom < 0 {
+		bottom = 0
+	}
+	if left < 0 {
+		left = 0
+	}
+	if top+right+bottom+left == 0 {
+		return i
+	}
+
+	src := i.image
+	b := src.Bounds()
+	w, h := b.Dx()+left+right, b.Dy()+top+bottom
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	inner := image.Rect(left, top, left+b.Dx(), top+b.Dy())
+
+	switch background.Mode {
+	case ExtendEdge, ExtendMirror:
+		if b.Empty() {
+			break
+		}
+		// each pixel around the image copies the pixel of the image it maps to
+		mirror := background.Mode == ExtendMirror
+		for y := 0; y < h; y++ {
+			sy := b.Min.Y + extendCoordinate(y-top, b.Dy(), mirror)
+			for x := 0; x < w; x++ {
+				if image.Pt(x, y).In(inner) {
+					continue
+				}
+				sx := b.Min.X + extendCoordinate(x-left, b.Dx(), mirror)
+				copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+			}
+		}
+	case ExtendBlur:
+		layer, err := blurredCover(src, w, h, background.Blur)
+		if err != nil {
+			i.addError(err)
+			return i
+		}
+		draw.Draw(dst, dst.Bounds(), layer, image.Point{}, draw.Src)
+	default:
+		if background.Color != nil {
+			draw.Draw(dst, dst.Bounds(), image.NewUniform(background.Color), image.Point{}, draw.Src)
+		}
+	}
+
+	draw.Draw(dst, inner, src, b.Min, draw.Over)
+
+	i.image = dst
+	i.width = w
+	i.height = h
+	return i
+}
+
+// PadTo fits the image in a width x height box and extends it to the size of the box, with the image
+// placed according to gravity. Images larger than the box are scaled down, keeping their aspect ratio.
+// background is how the added area is filled, default is transparent.
+func (i *Image) PadTo(width, height int, gravity Gravity, background ...Background) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if width <= 0 || height <= 0 {
+		return i
+	}
+
+	var bg Background
+	if len(background) > 0 {
+		bg = background[0]
+	}
+
+	// scale down to fit in the box
+	if i.width > width || i.height > height {
+		scale := math.Min(float64(width)/float64(i.width), float64(height)/float64(i.height))
+		w := int(math.Max(1, math.Round(float64(i.width)*scale)))
+		h := int(math.Max(1, math.Round(float64(i.height)*scale)))
+		resized := resize.Resize(uint(w), uint(h), i.image, resize.Lanczos3)
+		i.image = Image2RGBA(resized)
+		i.width = resized.Bounds().Dx()
+		i.height = resized.Bounds().Dy()
+	}
+
+	offset := gravityOffset(gravity, image.Pt(width-i.width, height-i.height))
+	return i.Extend(offset.Y, width-i.width-offset.X, height-i.height-offset.Y, offset.X, bg)
+}
+
+// gravityOffset returns the position of a rectangle in a container with free space around it,
+// according to gravity.
+func gravityOffset(gravity Gravity, free image.Point) image.Point {
+	x, y := free.X/2, free.Y/2
+	switch gravity {
+	case GravityNorth, GravityNorthEast, GravityNorthWest:
+		y = 0
+	case GravitySouth, GravitySouthEast, GravitySouthWest:
+		y = free.Y
+	}
+	switch gravity {
+	case GravityWest, GravityNorthWest, GravitySouthWest:
+		x = 0
+	case GravityEast, GravityNorthEast, GravitySouthEast:
+		x = free.X
+	}
+	return image.Pt(x, y)
+}
+
+// extendCoordinate maps the coordinate n outside of [0, size) into it, by clamping it to the
+// edges or by mirroring the range.
+func extendCoordinate(n, size int, mirror bool) int {
+	if !mirror {
+		if n < 0 {
+			return 0
+		}
+		if n >= size {
+			return size - 1
+		}
+		return n
+	}
+
+	period := 2 * size
+	n %= period
+	if n < 0 {
+		n += period
+	}
+	if n >= size {
+		n = period - 1 - n
+	}
+	return n
+}
+
+// blurredCover returns a blurred copy of src scaled to cover a width x height image, centered.
+// The blur is done on a smaller copy, which is faster and as smooth once scaled up.
+func blurredCover(src image.Image, width, height int, sigma float64) (*image.RGBA, error) {
+	if sigma <= 0 {
+		sigma = math.Max(float64(width), float64(height)) / 50
+	}
+
+	// the size of the copy, where the blur has a standard deviation of about 2 pixels
+	factor := math.Max(1, sigma/2)
+	w := int(math.Max(1, math.Ceil(float64(width)/factor)))
+	h := int(math.Max(1, math.Ceil(float64(height)/factor)))
+
+	// scale src to cover the small copy, and crop its center
+	b := src.Bounds()
+	scale := math.Max(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+	cw := int(math.Max(float64(w), math.Round(float64(b.Dx())*scale)))
+	ch := int(math.Max(float64(h), math.Round(float64(b.Dy())*scale)))
+	covered := resize.Resize(uint(cw), uint(ch), src, resize.Bilinear)
+	small := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(small, small.Bounds(), covered, covered.Bounds().Min.Add(image.Pt((cw-w)/2, (ch-h)/2)), draw.Src)
+
+	// blur with edges extended, so that the borders don't fade to transparent
+	stdDev := sigma / factor
+	margin := int(math.Ceil(3 * stdDev))
+	padded := image.NewRGBA(image.Rect(0, 0, w+2*margin, h+2*margin))
+	for y := 0; y < padded.Rect.Dy(); y++ {
+		for x := 0; x < padded.Rect.Dx(); x++ {
+			sx, sy := extendCoordinate(x-margin, w, false), extendCoordinate(y-margin, h, false)
+			copy(padded.Pix[padded.PixOffset(x, y):padded.PixOffset(x, y)+4], small.Pix[small.PixOffset(sx, sy):small.PixOffset(sx, sy)+4])
+		}
+	}
+	blurred := image.NewRGBA(padded.Bounds())
+	err := graphics.Blur(blurred, padded, &graphics.BlurOptions{StdDev: stdDev, Size: 2*margin + 1})
+	if err != nil {
+		return nil, err
+	}
+	small = blurred.SubImage(image.Rect(margin, margin, margin+w, margin+h)).(*image.RGBA)
+
+	return Image2RGBA(resize.Resize(uint(width), uint(height), Image2RGBA(small), resize.Bilinear)), nil
+}
@@ -0,0 +1,136 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// FloodFill fills the region of similar colors connected to (x, y) with c. The pixels of the region
+// are replaced, so a transparent color erases the region. To paint the region with a gradient, use
+// the mask of SelectRegion with PaintRect.
+// tolerance is the largest difference, from 0 to 255, of any RGBA channel with the color at (x, y).
+// connectivity is how pixels are connected, default is Connect4.
+func (i *Image) FloodFill(x, y int, c color.Color, tolerance int, connectivity ...Connectivity) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	mask := i.floodRegion(x, y, tolerance, connectivity...)
+	if mask == nil {
+		return i
+	}
+
+	// draw.Src would also clear the pixels outside of the mask, so the pixels are replaced one by one
+	b := mask.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if mask.Pix[mask.PixOffset(x, y)] != 0 {
+				i.image.Set(x, y, c)
+			}
+		}
+	}
+
+	return i
+}
+
+// SelectRegion returns a mask of the region of similar colors connected to (x, y), like a magic wand.
+// The mask has the size of the image, the region is opaque white and the rest is transparent.
+// tolerance and connectivity are the same as FloodFill. The image itself is not modified.
+func (i *Image) SelectRegion(x, y int, tolerance int, connectivity ...Connectivity) *Image {
+	if i.Error != nil {
+		return &Image{Error: i.Error}
+	}
+
+	dst := image.NewRGBA(i.image.Bounds())
+	if mask := i.floodRegion(x, y, tolerance, connectivity...); mask != nil {
+		draw.DrawMask(dst, mask.Bounds(), image.White, image.Point{}, mask, mask.Bounds().Min, draw.Over)
+	}
+
+	return &Image{
+		image:     dst,
+		width:     i.width,
+		height:    i.height,
+		extension: "png",
+		mimetype:  "image/png",
+	}
+}
+
+// floodRegion returns the mask of the region connected to (x, y), or nil if (x, y) is outside the image.
+// It fills spans of pixels row by row with an explicit stack, so large regions do not recurse.
+func (i *Image) floodRegion(x, y int, tolerance int, connectivity ...Connectivity) *image.Alpha {
+	img := i.image
+	bounds := img.Bounds()
+	if !image.Pt(x, y).In(bounds) {
+		return nil
+	}
+
+	diagonal := len(connectivity) > 0 && connectivity[0] == Connect8
+	seed := img.Pix[img.PixOffset(x, y):]
+	target := [4]int{int(seed[0]), int(seed[1]), int(seed[2]), int(seed[3])}
+
+	mask := image.NewAlpha(bounds)
+	inside := func(x, y int) bool {
+		if mask.Pix[mask.PixOffset(x, y)] != 0 {
+			return false
+		}
+		p := img.Pix[img.PixOffset(x, y):]
+		for c := 0; c < 4; c++ {
+			d := int(p[c]) - target[c]
+			if d > tolerance || -d > tolerance {
+				return false
+			}
+		}
+		return true
+	}
+
+	stack := []image.Point{{X: x, Y: y}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !inside(p.X, p.Y) {
+			continue
+		}
+
+		// grow the span to the left and to the right, and fill it
+		left, right := p.X, p.X
+		for left > bounds.Min.X && inside(left-1, p.Y) {
+			left--
+		}
+		for right < bounds.Max.X-1 && inside(right+1, p.Y) {
+			right++
+		}
+		offset := mask.PixOffset(left, p.Y)
+		for n := 0; n <= right-left; n++ {
+			mask.Pix[offset+n] = 0xff
+		}
+
+		// push one seed for each run of matching pixels in the rows above and below
+		if diagonal {
+			if left > bounds.Min.X {
+				left--
+			}
+			if right < bounds.Max.X-1 {
+				right++
+			}
+		}
+		for _, ny := range [2]int{p.Y - 1, p.Y + 1} {
+			if ny < bounds.Min.Y || ny >= bounds.Max.Y {
+				continue
+			}
+			run := false
+			for nx := left; nx <= right; nx++ {
+				if inside(nx, ny) {
+					if !run {
+						stack = append(stack, image.Pt(nx, ny))
+					}
+					run = true
+				} else {
+					run = false
+				}
+			}
+		}
+	}
+
+	return mask
+}
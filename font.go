@@ -0,0 +1,390 @@
+package imgo
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/draw"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FontRegistry loads TTF/OTF fonts once and caches their faces per size and DPI.
+// Fonts are referred to by family name, such as "Go", or by family name and style, such as
+// "Go Bold" or "Go Bold Italic". Names that are not registered are loaded as font file paths.
+// A FontRegistry is safe for concurrent use.
+type FontRegistry struct {
+	mu        sync.Mutex
+	families  map[string]map[FontStyle]*sfnt.Font // registered fonts by family and style
+	files     map[string]*sfnt.Font               // fonts loaded from a path
+	fallbacks map[string][]string                 // fallback chains by family name or path
+	faces     map[faceKey]*registryFace           // cached faces, at most maxCachedFaces
+	clock     uint64                              // counter of face lookups, to find the least recently used face
+}
+
+// the sizes of the font caches, and the number of horizontal sub-pixel positions glyphs are rendered at
+const (
+	maxCachedFaces    = 64
+	maxCachedGlyphs   = 2048
+	glyphSubpixelsX   = 4
+	glyphSubpixelStep = 64 / glyphSubpixelsX
+)
+
+// faceKey identifies a cached face.
+type faceKey struct {
+	name string
+	size float64
+	dpi  float64
+}
+
+// DefaultFontRegistry is the registry used by Text, TextBox and MeasureText.
+var DefaultFontRegistry = NewFontRegistry()
+
+// fontStyleSuffixes are the suffixes of font names that select a style of a family,
+// longest first so that "Bold Italic" isn't taken for "Italic".
+var fontStyleSuffixes = []struct {
+	suffix string
+	style  FontStyle
+}{
+	{" Bold Italic", FontBoldItalic},
+	{" Bold", FontBold},
+	{" Italic", FontItalic},
+}
+
+// NewFontRegistry creates a new empty font registry.
+func NewFontRegistry() *FontRegistry {
+	return &FontRegistry{
+		families:  make(map[string]map[FontStyle]*sfnt.Font),
+		files:     make(map[string]*sfnt.Font),
+		fallbacks: make(map[string][]string),
+		faces:     make(map[faceKey]*registryFace),
+	}
+}
+
+// RegisterFont registers a font to the default registry.
+// source can be a file path, an *os.File or a byte slice.
+func RegisterFont(family string, style FontStyle, source interface{}) error {
+	return DefaultFontRegistry.Register(family, style, source)
+}
+
+// RegisterFontFS registers a font read from a file system to the default registry.
+func RegisterFontFS(family string, style FontStyle, fsys fs.FS, path string) error {
+	return DefaultFontRegistry.RegisterFS(family, style, fsys, path)
+}
+
+// SetFontFallback sets the fallback chain of a font family in the default registry.
+func SetFontFallback(family string, fallbacks ...string) {
+	DefaultFontRegistry.SetFallback(family, fallbacks...)
+}
+
+// Register registers a font with given family name and style.
+// source can be a file path, an *os.File or a byte slice.
+func (r *FontRegistry) Register(family string, style FontStyle, source interface{}) error {
+	var fontBytes []byte
+	var err error
+	switch source.(type) {
+	case string:
+		fontBytes, err = ioutil.ReadFile(source.(string))
+	case *os.File:
+		fontBytes, err = ioutil.ReadAll(source.(*os.File))
+	case []byte:
+		fontBytes = source.([]byte)
+	default:
+		err = ErrFontSourceNotSupport
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.register(family, style, fontBytes)
+}
+
+// RegisterFS registers a font read from a file system with given family name and style.
+func (r *FontRegistry) RegisterFS(family string, style FontStyle, fsys fs.FS, path string) error {
+	fontBytes, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+
+	return r.register(family, style, fontBytes)
+}
+
+// register parses the font and adds it to the registry.
+func (r *FontRegistry) register(family string, style FontStyle, fontBytes []byte) error {
+	f, err := opentype.Parse(fontBytes)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.families[family] == nil {
+		r.families[family] = make(map[FontStyle]*sfnt.Font)
+	}
+	r.families[family][style] = f
+	r.clearFaces()
+
+	return nil
+}
+
+// SetFallback sets the fonts that are used, in order, for glyphs missing from the fonts of family.
+// family and fallbacks can be registered font names or font file paths.
+func (r *FontRegistry) SetFallback(family string, fallbacks ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallbacks[family] = fallbacks
+	r.clearFaces()
+}
+
+// clearFaces drops the cached faces, so that changes to the registry are seen by new faces.
+func (r *FontRegistry) clearFaces() {
+	r.faces = make(map[faceKey]*registryFace)
+}
+
+// Face returns the face of the font with given name, size in points and DPI.
+// The face is cached, so it must not be closed by the caller.
+func (r *FontRegistry) Face(name string, size, dpi float64) (font.Face, error) {
+	if dpi <= 0 {
+		dpi = 72
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clock++
+	key := faceKey{name: name, size: size, dpi: dpi}
+	if face, ok := r.faces[key]; ok {
+		face.used = r.clock
+		return face, nil
+	}
+
+	primary, family, err := r.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	fonts := []*sfnt.Font{primary}
+	for _, fallback := range r.fallbacks[family] {
+		f, _, err := r.lookup(fallback)
+		if err != nil {
+			return nil, err
+		}
+		fonts = append(fonts, f)
+	}
+
+	face := &registryFace{fonts: fonts, used: r.clock}
+	for _, f := range fonts {
+		ff, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: dpi, Hinting: font.HintingNone})
+		if err != nil {
+			return nil, err
+		}
+		face.faces = append(face.faces, ff)
+	}
+
+	// drop the least recently used face when the cache is full
+	if len(r.faces) >= maxCachedFaces {
+		var oldest faceKey
+		var oldestUse uint64
+		for k, f := range r.faces {
+			if oldestUse == 0 || f.used < oldestUse {
+				oldest, oldestUse = k, f.used
+			}
+		}
+		delete(r.faces, oldest)
+	}
+	r.faces[key] = face
+
+	return face, nil
+}
+
+// lookup finds the font with given name, and returns it with the family name its fallbacks are
+// registered under. A style that isn't registered falls back to the regular style of the family.
+func (r *FontRegistry) lookup(name string) (*sfnt.Font, string, error) {
+	if styles, ok := r.families[name]; ok {
+		return r.style(styles, FontRegular), name, nil
+	}
+
+	for _, s := range fontStyleSuffixes {
+		family := strings.TrimSuffix(name, s.suffix)
+		if styles, ok := r.families[family]; ok && family != name {
+			return r.style(styles, s.style), family, nil
+		}
+	}
+
+	// not a registered family, load it as a file path
+	if f, ok := r.files[name]; ok {
+		return f, name, nil
+	}
+	fontBytes, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, name, err
+	}
+	f, err := opentype.Parse(fontBytes)
+	if err != nil {
+		return nil, name, err
+	}
+	r.files[name] = f
+
+	return f, name, nil
+}
+
+// style returns the font of given style, or the regular one if the style isn't registered.
+func (r *FontRegistry) style(styles map[FontStyle]*sfnt.Font, style FontStyle) *sfnt.Font {
+	if f, ok := styles[style]; ok {
+		return f
+	}
+	if f, ok := styles[FontRegular]; ok {
+		return f
+	}
+	for _, f := range styles {
+		return f
+	}
+	return nil
+}
+
+// registryFace is a font.Face shared by all users of a registry, that renders each glyph with
+// the first font of its fallback chain that has it.
+// Rendered glyphs are cached, so that concurrent users don't wait for each other.
+type registryFace struct {
+	mu    sync.Mutex
+	fonts []*sfnt.Font
+	faces []font.Face
+	buf   sfnt.Buffer
+	used  uint64 // last lookup of the face in the registry, guarded by the registry
+
+	glyphsMu sync.RWMutex
+	glyphs   map[glyphKey]glyph
+}
+
+// glyphKey is a rune at a horizontal sub-pixel position, the quantized fractional part of the dot.
+type glyphKey struct {
+	r  rune
+	dx fixed.Int26_6
+}
+
+// glyph is a rendered glyph, with its rectangle relative to the integer part of the dot.
+type glyph struct {
+	dr      image.Rectangle
+	mask    image.Image
+	maskp   image.Point
+	advance fixed.Int26_6
+	ok      bool
+}
+
+// pick returns the index of the first face that has a glyph for r, or 0 if none has.
+func (f *registryFace) pick(r rune) int {
+	if n := f.find(r); n >= 0 {
+		return n
+	}
+	return 0
+}
+
+// find returns the index of the first face that has a glyph for r, or -1 if none has.
+func (f *registryFace) find(r rune) int {
+	for n, ft := range f.fonts {
+		if index, err := ft.GlyphIndex(&f.buf, r); err == nil && index != 0 {
+			return n
+		}
+	}
+	return -1
+}
+
+// hasGlyph reports whether any font of the fallback chain has a glyph for r.
+func (f *registryFace) hasGlyph(r rune) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.find(r) >= 0
+}
+
+// Close does nothing, the face is owned by the registry.
+func (f *registryFace) Close() error {
+	return nil
+}
+
+// Glyph returns the glyph of r. Glyphs are rendered once per sub-pixel position and cached,
+// the masks are shared and must not be modified.
+func (f *registryFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	// like freetype, the dot is rounded to one of glyphSubpixelsX positions horizontally and to whole pixels vertically
+	x := dot.X + glyphSubpixelStep/2
+	key := glyphKey{r: r, dx: x & 63 &^ (glyphSubpixelStep - 1)}
+	offset := image.Pt(x.Floor(), dot.Y.Round())
+
+	f.glyphsMu.RLock()
+	g, cached := f.glyphs[key]
+	f.glyphsMu.RUnlock()
+	if !cached {
+		g = f.render(key)
+	}
+	if !g.ok {
+		return image.Rectangle{}, nil, image.Point{}, g.advance, false
+	}
+
+	return g.dr.Add(offset), g.mask, g.maskp, g.advance, true
+}
+
+// render renders the glyph of key at a dot with no integer part, and caches it.
+// The mask is a copy, since the underlying face reuses its buffer. The cache is emptied when it is full.
+func (f *registryFace) render(key glyphKey) glyph {
+	f.mu.Lock()
+	var g glyph
+	var mask image.Image
+	g.dr, mask, g.maskp, g.advance, g.ok = f.faces[f.pick(key.r)].Glyph(fixed.Point26_6{X: key.dx}, key.r)
+	if g.ok && mask != nil {
+		copied := image.NewAlpha(mask.Bounds())
+		draw.Draw(copied, copied.Bounds(), mask, mask.Bounds().Min, draw.Src)
+		g.mask = copied
+	}
+	f.mu.Unlock()
+
+	f.glyphsMu.Lock()
+	if f.glyphs == nil || len(f.glyphs) >= maxCachedGlyphs {
+		f.glyphs = make(map[glyphKey]glyph)
+	}
+	f.glyphs[key] = g
+	f.glyphsMu.Unlock()
+
+	return g
+}
+
+// GlyphBounds returns the bounding box of r.
+func (f *registryFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.faces[f.pick(r)].GlyphBounds(r)
+}
+
+// GlyphAdvance returns the advance width of r.
+func (f *registryFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.faces[f.pick(r)].GlyphAdvance(r)
+}
+
+// Kern returns the kerning between r0 and r1, which is 0 if they come from different fonts.
+func (f *registryFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := f.pick(r0)
+	if n != f.pick(r1) {
+		return 0
+	}
+	return f.faces[n].Kern(r0, r1)
+}
+
+// Metrics returns the metrics of the primary font.
+func (f *registryFace) Metrics() font.Metrics {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.faces[0].Metrics()
+}
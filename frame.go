@@ -0,0 +1,76 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// PolaroidOptions is the options of Polaroid.
+type PolaroidOptions struct {
+	Margin         int         // width of the frame on the top, left and right sides, default is 6% of the image width
+	BottomMargin   int         // height of the frame on the bottom side, default is 4 times the margin
+	Color          color.Color // color of the frame, default is white
+	Caption        string      // text written in the bottom of the frame
+	CaptionOptions TextOptions // font and style of the caption, it is centered in the bottom of the frame
+}
+
+// Border draws a border of given width and color around the image, growing the image.
+func (i *Image) Border(width int, c color.Color) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if width <= 0 {
+		return i
+	}
+
+	return i.frame(width, width, width, width, c)
+}
+
+// Polaroid puts the image in a polaroid-style frame, with a larger bottom side and an optional caption.
+func (i *Image) Polaroid(options ...PolaroidOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	var opt PolaroidOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Margin <= 0 {
+		opt.Margin = (i.width*6 + 99) / 100
+	}
+	if opt.BottomMargin <= 0 {
+		opt.BottomMargin = 4 * opt.Margin
+	}
+	if opt.Color == nil {
+		opt.Color = color.White
+	}
+
+	i.frame(opt.Margin, opt.Margin, opt.BottomMargin, opt.Margin, opt.Color)
+
+	if opt.Caption != "" {
+		captionOptions := opt.CaptionOptions
+		captionOptions.Align = AlignCenter
+		captionOptions.VerticalAlign = AlignMiddle
+		rect := image.Rect(opt.Margin, i.height-opt.BottomMargin, i.width-opt.Margin, i.height)
+		i.TextBox(opt.Caption, rect, captionOptions)
+	}
+
+	return i
+}
+
+// frame grows the image by the given sizes on each side, filled with c.
+// The image is drawn over the frame, so the frame shows through its transparent pixels.
+func (i *Image) frame(top, right, bottom, left int, c color.Color) *Image {
+	bounds := i.image.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx()+left+right, bounds.Dy()+top+bottom))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	draw.Draw(dst, bounds.Sub(bounds.Min).Add(image.Pt(left, top)), i.image, bounds.Min, draw.Over)
+
+	i.image = dst
+	i.width = dst.Bounds().Dx()
+	i.height = dst.Bounds().Dy()
+	return i
+}
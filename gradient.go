@@ -0,0 +1,325 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+	"sync"
+)
+
+// GradientStop is a color stop of a gradient.
+type GradientStop struct {
+	Offset float64     // position of the stop along the gradient, between 0 and 1
+	Color  color.Color // color of the stop
+}
+
+// gradientKind is the geometry of a gradient.
+type gradientKind int
+
+const (
+	linearGradient gradientKind = iota
+	radialGradient
+	conicGradient
+)
+
+// gradientSteps is the number of precomputed colors of a gradient.
+const gradientSteps = 1024
+
+// Gradient is a linear, radial or conic gradient with multiple color stops.
+//
+// A Gradient is an image.Image of infinite size, in image coordinates. It is painted as the
+// source of PaintRect, the Fill of ShapeStyle and TextOptions, the fill and stroke source of
+// Context, and it can be the mask of PaintRect and FillRect.
+// AddStop, SetSpread and SetInterpolation return a new gradient, so a Gradient can be shared.
+type Gradient struct {
+	kind          gradientKind
+	p0, p1        Point   // start and end of a linear gradient, or center of a radial or conic one
+	radius        float64 // radius of a radial gradient
+	angle         float64 // start angle of a conic gradient, in radians
+	stops         []GradientStop
+	spread        SpreadMode
+	interpolation ColorInterpolation
+
+	once   sync.Once
+	colors []color.RGBA64 // precomputed colors along the gradient
+}
+
+// NewLinearGradient creates a gradient along the line from (x0, y0) to (x1, y1).
+func NewLinearGradient(x0, y0, x1, y1 float64, stops ...GradientStop) *Gradient {
+	return &Gradient{kind: linearGradient, p0: Pt(x0, y0), p1: Pt(x1, y1), stops: stops}
+}
+
+// NewRadialGradient creates a gradient from the center (cx, cy) to the circle of given radius.
+func NewRadialGradient(cx, cy, radius float64, stops ...GradientStop) *Gradient {
+	return &Gradient{kind: radialGradient, p0: Pt(cx, cy), radius: radius, stops: stops}
+}
+
+// NewConicGradient creates a gradient sweeping clockwise around the center (cx, cy),
+// starting at given angle in degrees, where 0 points to the right.
+func NewConicGradient(cx, cy, angle float64, stops ...GradientStop) *Gradient {
+	return &Gradient{kind: conicGradient, p0: Pt(cx, cy), angle: angle * math.Pi / 180, stops: stops}
+}
+
+// AddStop returns a copy of the gradient with a color stop at offset, between 0 and 1.
+func (g *Gradient) AddStop(offset float64, c color.Color) *Gradient {
+	n := g.copy()
+	n.stops = append(n.stops, GradientStop{Offset: offset, Color: c})
+	return n
+}
+
+// SetSpread returns a copy of the gradient painted with spread outside of the range from 0 to 1,
+// default is SpreadPad.
+func (g *Gradient) SetSpread(spread SpreadMode) *Gradient {
+	n := g.copy()
+	n.spread = spread
+	return n
+}
+
+// SetInterpolation returns a copy of the gradient with colors interpolated in given color space,
+// default is InterpolateSRGB.
+func (g *Gradient) SetInterpolation(interpolation ColorInterpolation) *Gradient {
+	n := g.copy()
+	n.interpolation = interpolation
+	return n
+}
+
+// copy returns a copy of the gradient without its precomputed colors.
+func (g *Gradient) copy() *Gradient {
+	return &Gradient{
+		kind:          g.kind,
+		p0:            g.p0,
+		p1:            g.p1,
+		radius:        g.radius,
+		angle:         g.angle,
+		stops:         append([]GradientStop(nil), g.stops...),
+		spread:        g.spread,
+		interpolation: g.interpolation,
+	}
+}
+
+// ColorModel returns the color model of the gradient.
+func (g *Gradient) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+// Bounds returns a very large rectangle, as the gradient is infinite.
+func (g *Gradient) Bounds() image.Rectangle {
+	return image.Rect(-1e9, -1e9, 1e9, 1e9)
+}
+
+// At returns the color of the gradient at the center of pixel (x, y).
+func (g *Gradient) At(x, y int) color.Color {
+	return g.colorAt(g.offset(float64(x)+0.5, float64(y)+0.5))
+}
+
+// offset returns the position of the point (x, y) along the gradient, before spreading.
+func (g *Gradient) offset(x, y float64) float64 {
+	switch g.kind {
+	case radialGradient:
+		if g.radius <= 0 {
+			return 1
+		}
+		return math.Hypot(x-g.p0.X, y-g.p0.Y) / g.radius
+	case conicGradient:
+		a := math.Atan2(y-g.p0.Y, x-g.p0.X) - g.angle
+		return a / (2 * math.Pi)
+	default:
+		d := g.p1.Sub(g.p0)
+		length := d.X*d.X + d.Y*d.Y
+		if length == 0 {
+			return 0
+		}
+		return ((x-g.p0.X)*d.X + (y-g.p0.Y)*d.Y) / length
+	}
+}
+
+// colorAt returns the color at offset t, after spreading.
+func (g *Gradient) colorAt(t float64) color.RGBA64 {
+	g.once.Do(g.precompute)
+	if len(g.colors) == 0 {
+		return color.RGBA64{}
+	}
+
+	spread := g.spread
+	if g.kind == conicGradient {
+		spread = SpreadRepeat // a conic gradient wraps around the center
+	}
+	switch spread {
+	case SpreadRepeat:
+		t -= math.Floor(t)
+	case SpreadReflect:
+		t = math.Mod(math.Abs(t), 2)
+		if t > 1 {
+			t = 2 - t
+		}
+	}
+	t = math.Max(0, math.Min(1, t))
+
+	return g.colors[int(t*(gradientSteps-1)+0.5)]
+}
+
+// precompute interpolates the colors along the gradient.
+func (g *Gradient) precompute() {
+	if len(g.stops) == 0 {
+		return
+	}
+
+	stops := make([]GradientStop, len(g.stops))
+	copy(stops, g.stops)
+	sort.SliceStable(stops, func(a, b int) bool { return stops[a].Offset < stops[b].Offset })
+
+	// the premultiplied colors of the stops in the interpolation color space
+	values := make([][4]float64, len(stops))
+	for n, stop := range stops {
+		values[n] = g.toSpace(stop.Color)
+	}
+
+	g.colors = make([]color.RGBA64, gradientSteps)
+	next := 0
+	for k := range g.colors {
+		t := float64(k) / (gradientSteps - 1)
+		for next < len(stops) && stops[next].Offset <= t {
+			next++
+		}
+
+		var v [4]float64
+		switch {
+		case next == 0:
+			v = values[0]
+		case next == len(stops):
+			v = values[len(stops)-1]
+		default:
+			a, b := stops[next-1], stops[next]
+			f := 0.0
+			if b.Offset > a.Offset {
+				f = (t - a.Offset) / (b.Offset - a.Offset)
+			}
+			for c := range v {
+				v[c] = values[next-1][c] + (values[next][c]-values[next-1][c])*f
+			}
+		}
+		g.colors[k] = g.fromSpace(v)
+	}
+}
+
+// toSpace converts a color to premultiplied components in the interpolation color space.
+func (g *Gradient) toSpace(c color.Color) [4]float64 {
+	n := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	r, gg, b, a := float64(n.R)/0xffff, float64(n.G)/0xffff, float64(n.B)/0xffff, float64(n.A)/0xffff
+
+	switch g.interpolation {
+	case InterpolateLinearRGB:
+		r, gg, b = srgbToLinear(r), srgbToLinear(gg), srgbToLinear(b)
+	case InterpolateOKLab:
+		r, gg, b = linearToOKLab(srgbToLinear(r), srgbToLinear(gg), srgbToLinear(b))
+	}
+
+	return [4]float64{r * a, gg * a, b * a, a}
+}
+
+// fromSpace converts premultiplied components in the interpolation color space to a color.
+func (g *Gradient) fromSpace(v [4]float64) color.RGBA64 {
+	a := v[3]
+	if a <= 0 {
+		return color.RGBA64{}
+	}
+	r, gg, b := v[0]/a, v[1]/a, v[2]/a
+
+	switch g.interpolation {
+	case InterpolateLinearRGB:
+		r, gg, b = linearToSRGB(r), linearToSRGB(gg), linearToSRGB(b)
+	case InterpolateOKLab:
+		r, gg, b = okLabToLinear(r, gg, b)
+		r, gg, b = linearToSRGB(r), linearToSRGB(gg), linearToSRGB(b)
+	}
+
+	channel := func(x float64) uint16 {
+		return uint16(math.Max(0, math.Min(1, x))*a*0xffff + 0.5)
+	}
+	return color.RGBA64{R: channel(r), G: channel(gg), B: channel(b), A: uint16(math.Min(1, a)*0xffff + 0.5)}
+}
+
+// srgbToLinear converts an sRGB component to linear RGB.
+func srgbToLinear(x float64) float64 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return math.Pow((x+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts a linear RGB component to sRGB.
+func linearToSRGB(x float64) float64 {
+	if x <= 0.0031308 {
+		return x * 12.92
+	}
+	return 1.055*math.Pow(x, 1/2.4) - 0.055
+}
+
+// linearToOKLab converts linear RGB to OKLab.
+func linearToOKLab(r, g, b float64) (float64, float64, float64) {
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// okLabToLinear converts OKLab to linear RGB.
+func okLabToLinear(L, a, b float64) (float64, float64, float64) {
+	l := L + 0.3963377774*a + 0.2158037573*b
+	m := L - 0.1055613458*a - 0.0638541728*b
+	s := L - 0.0894841775*a - 1.2914855480*b
+	l, m, s = l*l*l, m*m*m, s*s*s
+	return 4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		-1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		-0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+}
+
+// opacitySource is an image with its alpha multiplied by an opacity.
+type opacitySource struct {
+	src     image.Image
+	opacity float64
+}
+
+func (s *opacitySource) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (s *opacitySource) Bounds() image.Rectangle {
+	return s.src.Bounds()
+}
+
+func (s *opacitySource) At(x, y int) color.Color {
+	return withOpacity(s.src.At(x, y), s.opacity)
+}
+
+// FillRect paints the rectangle with c.
+// mask is an optional image whose alpha channel controls the coverage of each pixel,
+// for example a gradient from opaque to transparent fades the fill out.
+func (i *Image) FillRect(rect image.Rectangle, c color.Color, mask ...image.Image) *Image {
+	return i.PaintRect(rect, image.NewUniform(c), mask...)
+}
+
+// PaintRect paints the rectangle with src, such as a gradient, sampled in image coordinates.
+// mask is an optional image whose alpha channel controls the coverage of each pixel.
+func (i *Image) PaintRect(rect image.Rectangle, src image.Image, mask ...image.Image) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	rect = rect.Intersect(i.image.Bounds())
+	if rect.Empty() {
+		return i
+	}
+
+	if len(mask) > 0 && mask[0] != nil {
+		draw.DrawMask(i.image, rect, src, rect.Min, mask[0], rect.Min, draw.Over)
+	} else {
+		draw.Draw(i.image, rect, src, rect.Min, draw.Over)
+	}
+
+	return i
+}
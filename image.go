@@ -4,17 +4,16 @@ import (
 	"fmt"
 	"github.com/BurntSushi/graphics-go/graphics"
 	cliColor "github.com/fatih/color"
-	"github.com/golang/freetype"
 	"github.com/nfnt/resize"
 	"golang.org/x/image/bmp"
 	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 	"golang.org/x/image/tiff"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/jpeg"
 	"image/png"
-	"io/ioutil"
 	"log"
 	"math"
 	"net/http"
@@ -59,18 +58,26 @@ func (i *Image) addError(err error, OnlyReason ...bool) {
 
 	_, file, line, ok := runtime.Caller(1)
 
+	if err == nil {
+		return
+	}
+
+	// the message is formatted for the log, the errors are kept for errors.Is and errors.As
+	var msg string
+	if ok && !onlyReason {
+		msg = fmt.Sprintf("%v %v", yellow(file, ":", line), magenta("Error: ", err.Error()))
+	} else {
+		msg = err.Error()
+	}
+
 	if i.Error == nil {
 		if ok && !onlyReason {
-			i.Error = fmt.Errorf("%v %v", yellow(file, ":", line), magenta("Error: ", err.Error()))
+			i.Error = &imgoError{msg: msg, errs: []error{err}}
 		} else {
 			i.Error = err
 		}
-	} else if err != nil {
-		if ok && !onlyReason {
-			i.Error = fmt.Errorf("%v\n%v %v", i.Error, yellow(file, ":", line), magenta("Error: ", err.Error()))
-		} else {
-			i.Error = fmt.Errorf("%v\n%v", i.Error, err)
-		}
+	} else {
+		i.Error = &imgoError{msg: i.Error.Error() + "\n" + msg, errs: []error{i.Error, err}}
 	}
 }
 
@@ -104,6 +111,16 @@ func (i Image) Bounds() image.Rectangle {
 	return i.image.Bounds()
 }
 
+// Clone returns a deep copy of the image, so that the copy can be modified
+// without affecting the original one.
+func (i Image) Clone() *Image {
+	clone := i
+	if i.image != nil {
+		clone.image = Image2RGBA(i.image)
+	}
+	return &clone
+}
+
 // Insert inserts source into the image at given (x, y) coordinate.
 // source can be a file path, a URL, a base64 encoded string, an *os.File, an image.Image,
 // a byte slice or an *Image.
@@ -210,12 +227,14 @@ func (i *Image) Save(path string, quality ...int) *Image {
 }
 
 // Resize resizes the image to the specified width and height.
+// A width or height of 0 keeps the aspect ratio. The image is left unchanged if both sizes
+// are the current ones, or both are 0.
 func (i *Image) Resize(width, height int) *Image {
 	if i.Error != nil {
 		return i
 	}
 
-	if width == i.width || height == i.height || (width == 0 && height == 0) {
+	if (width == i.width && height == i.height) || (width == 0 && height == 0) {
 		return i
 	}
 
@@ -228,54 +247,86 @@ func (i *Image) Resize(width, height int) *Image {
 }
 
 // Crop Cut out a rectangular part of the current image with given width and height.
+// The region is clamped to the image. A width or height of 0 or less, or a region entirely
+// outside of the image, leaves the image unchanged and adds an error of type *CropError.
 func (i *Image) Crop(x, y, width, height int) *Image {
 	if i.Error != nil {
 		return i
 	}
 
-	if width == i.width || height == i.height || width == 0 || height == 0 || x > i.width || y > i.height {
-		return i
-	}
-
-	x1 := x + width
-	y1 := y + height
-	clipped := i.image.SubImage(image.Rect(x, y, x1, y1))
-	i.image = Image2RGBA(clipped)
-	i.width = width
-	i.height = height
+	return i.cropRect(image.Rectangle{Min: image.Pt(x, y), Max: image.Pt(x+width, y+height)})
+}
 
-	return i
+// RotateOptions is the options of RotateAngle.
+type RotateOptions struct {
+	Interpolation Interpolation // how pixels are interpolated, default is InterpolationBilinear
+	Background    color.Color   // color of the corners uncovered by the rotation, default is transparent
+	Mode          RotateMode    // size of the result, default is RotateExpand, which fits the whole rotated image
+	Center        *Point        // center of the rotation with RotateKeep, default is the center of the image
 }
 
-// Rotate rotates the image clockwise by the specified angle.
+// Rotate rotates the image clockwise by the specified angle in degrees, growing it to fit the
+// rotated image. It is RotateAngle with the default options.
 func (i *Image) Rotate(angle int) *Image {
+	return i.RotateAngle(float64(angle))
+}
+
+// RotateAngle rotates the image clockwise by the specified angle in degrees, negative angles rotate
+// counter-clockwise. Multiples of 90 degrees move the pixels exactly.
+// With RotateExpand the image grows to fit the rotated image, with RotateKeep it keeps its size,
+// and with RotateCrop it is cropped to the largest rectangle inside the rotated image.
+func (i *Image) RotateAngle(angle float64, options ...RotateOptions) *Image {
 	if i.Error != nil {
 		return i
 	}
-	angle %= 360
-	if angle == 0 {
-		return i
+
+	var opt RotateOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+
+	// quarter turns move the pixels exactly, unless the size is kept or the center is moved
+	if quarter := angle / 90; quarter == math.Trunc(quarter) {
+		if opt.Mode != RotateKeep || (int(quarter)%2 == 0 && opt.Center == nil) {
+			return i.reorient(rotations[int(quarter)])
+		}
 	}
 
-	// angle to radian
-	radian := float64(angle) * math.Pi / 180.0
-	cos := math.Cos(radian)
-	sin := math.Sin(radian)
+	w, h := float64(i.width), float64(i.height)
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	center := Pt(w/2, h/2)
+	if opt.Mode == RotateKeep && opt.Center != nil {
+		center = *opt.Center
+	}
 
-	w := float64(i.width)
-	h := float64(i.height)
+	// the size of the result, with the center of the rotation at its center for RotateExpand and RotateCrop
+	W, H := i.width, i.height
+	switch opt.Mode {
+	case RotateExpand:
+		W = int(math.Ceil(w*math.Abs(cos) + h*math.Abs(sin) - 1e-6))
+		H = int(math.Ceil(w*math.Abs(sin) + h*math.Abs(cos) - 1e-6))
+	case RotateCrop:
+		cw, ch := inscribedRect(w, h, sin, cos)
+		W, H = int(math.Max(1, math.Floor(cw+1e-6))), int(math.Max(1, math.Floor(ch+1e-6)))
+	}
+	target := center
+	if opt.Mode != RotateKeep {
+		target = Pt(float64(W)/2, float64(H)/2)
+	}
 
-	// calculate the new image size
-	W := int(math.Max(math.Abs(w*cos-h*sin), math.Abs(w*cos+h*sin)))
-	H := int(math.Max(math.Abs(w*sin-h*cos), math.Abs(w*sin+h*cos)))
+	m := Identity().Translate(target.X, target.Y).Rotate(angle).Translate(-center.X, -center.Y)
+	inverse, _ := m.Invert()
+	origin := i.image.Bounds().Min
 
-	// rotate the image
 	dst := image.NewRGBA(image.Rect(0, 0, W, H))
-	err := graphics.Rotate(dst, i.image, &graphics.RotateOptions{Angle: radian})
-	if err != nil {
-		i.addError(err)
-		return i
-	}
+	warp(dst, i.image, opt.Interpolation, opt.Background, func(p Point) (Point, bool) {
+		return inverse.Apply(p).Add(Pt(float64(origin.X), float64(origin.Y))), true
+	})
 
 	i.image = dst
 	i.width = W
@@ -283,6 +334,25 @@ func (i *Image) Rotate(angle int) *Image {
 	return i
 }
 
+// inscribedRect returns the size of the largest axis-aligned rectangle inside a w x h rectangle
+// rotated by an angle of given sine and cosine, centered on it.
+func inscribedRect(w, h, sin, cos float64) (float64, float64) {
+	sin, cos = math.Abs(sin), math.Abs(cos)
+	long, short := math.Max(w, h), math.Min(w, h)
+
+	// a thin rectangle, or at 45 degrees, the rectangle touches the long sides only
+	if short <= 2*sin*cos*long || math.Abs(sin-cos) < 1e-10 {
+		x := short / 2
+		if w >= h {
+			return x / sin, x / cos
+		}
+		return x / cos, x / sin
+	}
+
+	cos2 := cos*cos - sin*sin
+	return (w*cos - h*sin) / cos2, (h*cos - w*sin) / cos2
+}
+
 // Grayscale converts the image to grayscale.
 func (i *Image) Grayscale() *Image {
 	if i.Error != nil {
@@ -309,71 +379,89 @@ func (i *Image) Flip(flipType FlipType) *Image {
 	}
 
 	if flipType == Horizontal {
-		i.flipHorizontally()
+		i.reorient(flipHorizontal)
 	} else if flipType == Vertical {
-		i.flipVertically()
+		i.reorient(flipVertical)
 	}
 
 	return i
 }
 
-// flipHorizontally flips the image horizontally.
+// flipHorizontally flips the image horizontally, reversing the pixels of each row in place.
 func (i *Image) flipHorizontally() {
-	for x := 0; x < i.width/2; x++ {
-		for y := 0; y < i.height; y++ {
-			pixel := i.image.At(x, y)
-			i.image.Set(x, y, i.image.At(i.width-x-1, y))
-			i.image.Set(i.width-x-1, y, pixel)
+	b := i.image.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := i.image.Pix[i.image.PixOffset(b.Min.X, y):i.image.PixOffset(b.Max.X, y)]
+		for l, r := 0, len(row)-4; l < r; l, r = l+4, r-4 {
+			for c := 0; c < 4; c++ {
+				row[l+c], row[r+c] = row[r+c], row[l+c]
+			}
 		}
 	}
 }
 
-// flipVertically flips the image vertically.
+// flipVertically flips the image vertically, swapping the rows in place.
 func (i *Image) flipVertically() {
-	for y := 0; y < i.height/2; y++ {
-		for x := 0; x < i.width; x++ {
-			pixel := i.image.At(x, y)
-			i.image.Set(x, y, i.image.At(x, i.height-y-1))
-			i.image.Set(x, i.height-y-1, pixel)
-		}
+	b := i.image.Bounds()
+	n := 4 * b.Dx()
+	tmp := make([]uint8, n)
+	for top, bottom := b.Min.Y, b.Max.Y-1; top < bottom; top, bottom = top+1, bottom-1 {
+		t := i.image.Pix[i.image.PixOffset(b.Min.X, top):][:n]
+		u := i.image.Pix[i.image.PixOffset(b.Min.X, bottom):][:n]
+		copy(tmp, t)
+		copy(t, u)
+		copy(u, tmp)
 	}
 }
 
 // Text write a text string to the image at given (x, y) coordinate.
-func (i *Image) Text(label string, x, y int, fontPath string, fontColor color.Color, fontSize float64, dpi float64) *Image {
+// fontPath is the path of the font file, or the name of a font registered in DefaultFontRegistry
+// such as "Go" or "Go Bold".
+// options adds the effects of TextOptions: LetterSpacing, Fill, StrokeWidth, StrokeColor, Shadow and
+// Background. Its font, color and layout fields are ignored, use TextBox to lay out text in a box.
+func (i *Image) Text(label string, x, y int, fontPath string, fontColor color.Color, fontSize float64, dpi float64, options ...TextOptions) *Image {
 	if i.Error != nil {
 		return i
 	}
 
-	// Load font
-	fontBytes, err := ioutil.ReadFile(fontPath)
-	if err != nil {
-		i.addError(err)
-		return i
+	// the registry treats a dpi of 0 as 72, and so does the baseline offset
+	if dpi <= 0 {
+		dpi = 72
 	}
-	myFont, err := freetype.ParseFont(fontBytes)
+
+	// Load font, parsed fonts and faces are cached by the registry
+	face, err := DefaultFontRegistry.Face(fontPath, fontSize, dpi)
 	if err != nil {
 		i.addError(err)
 		return i
 	}
+	dot := fixed.P(x, y+int(fontSize*dpi/72))
 
-	c := freetype.NewContext()
-	c.SetDPI(dpi)
-	c.SetFont(myFont)
-	c.SetFontSize(fontSize)
-	c.SetClip(i.Bounds())
-	c.SetDst(i.image)
-	uni := image.NewUniform(fontColor)
-	c.SetSrc(uni)
-	c.SetHinting(font.HintingNone)
-
-	// Draw text
-	pt := freetype.Pt(x, y+int(c.PointToFixed(fontSize)>>6))
-	if _, err := c.DrawString(label, pt); err != nil {
-		i.addError(err)
+	if len(options) == 0 {
+		// Draw text
+		d := font.Drawer{
+			Dst:  i.image,
+			Src:  image.NewUniform(fontColor),
+			Face: face,
+			Dot:  dot,
+		}
+		d.DrawString(label)
 		return i
 	}
 
+	// with effects, the glyphs are drawn into a mask first, like TextBox does
+	opt := options[0]
+	opt.Color = fontColor
+	metrics := face.Metrics()
+	spacing := fixed.Int26_6(opt.LetterSpacing * 64)
+	width := measureString(face, label, spacing)
+	margin := metrics.Height.Ceil()
+	top, bottom := (dot.Y - metrics.Ascent).Floor(), (dot.Y + metrics.Descent).Ceil()
+
+	mask := image.NewAlpha(image.Rect(x-margin, top-margin, (dot.X+width).Ceil()+margin, bottom+margin))
+	drawString(mask, image.Opaque, face, dot, label, spacing)
+	i.drawText(mask, image.Rect(x, top, (dot.X+width).Ceil(), bottom), opt)
+
 	return i
 }
 
@@ -0,0 +1,181 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// GridOptions is the options of Grid.
+type GridOptions struct {
+	Spacing    int         // space between the cells, in pixels
+	Background color.Color // color of the spacing and the empty parts of the cells, default is transparent
+	Gravity    Gravity     // position of the images in their cells, default is GravityCenter
+}
+
+// Append joins the images side by side, from left to right with DirectionHorizontal or from top to
+// bottom with DirectionVertical, spacing pixels apart, for example for before and after comparisons.
+// Images of different sizes are placed across the direction according to gravity, for example
+// GravityNorth aligns the tops of a horizontal row and GravityEast the right edges of a vertical stack.
+// The images can be any source Load accepts. background fills the spacing and the space around
+// the smaller images, default is transparent.
+func Append(images []interface{}, direction Direction, spacing int, gravity Gravity, background ...color.Color) *Image {
+	loaded, err := loadAll(images)
+	if err != nil {
+		i := &Image{}
+		i.addError(err, true)
+		return i
+	}
+
+	if spacing < 0 {
+		spacing = 0
+	}
+
+	// the length along the direction, and the largest size across it
+	length, across := spacing*(len(loaded)-1), 0
+	for _, img := range loaded {
+		along, other := img.width, img.height
+		if direction == DirectionVertical {
+			along, other = other, along
+		}
+		length += along
+		if other > across {
+			across = other
+		}
+	}
+
+	width, height := length, across
+	if direction == DirectionVertical {
+		width, height = across, length
+	}
+	i := Canvas(width, height, background...)
+
+	position := 0
+	for _, img := range loaded {
+		along, other := img.width, img.height
+		if direction == DirectionVertical {
+			along, other = other, along
+		}
+
+		if direction == DirectionVertical {
+			i.Insert(img, gravityOffset(gravity, image.Pt(across-other, 0)).X, position)
+		} else {
+			i.Insert(img, position, gravityOffset(gravity, image.Pt(0, across-other)).Y)
+		}
+		position += along + spacing
+	}
+
+	return i
+}
+
+// Grid lays out the images row by row in a grid of columns, each in a cell of cellWidth x cellHeight,
+// for example for product strips. A cell size of 0 is the largest size of the images.
+// fit is how the images are scaled to their cells: FitContain scales them to fit inside, FitCover
+// scales them to cover the cell and crops the rest, FitFill stretches them, and FitNone keeps their
+// size, cropping larger images. The images can be any source Load accepts, and are not modified.
+func Grid(images []interface{}, columns, cellWidth, cellHeight int, fit Fit, options ...GridOptions) *Image {
+	loaded, err := loadAll(images)
+	if err != nil {
+		i := &Image{}
+		i.addError(err, true)
+		return i
+	}
+
+	var opt GridOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.Spacing < 0 {
+		opt.Spacing = 0
+	}
+	if opt.Background == nil {
+		opt.Background = color.Transparent
+	}
+
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(len(loaded)))))
+	}
+	if columns > len(loaded) {
+		columns = len(loaded)
+	}
+	rows := (len(loaded) + columns - 1) / columns
+
+	// the default cell size fits the largest image
+	if cellWidth <= 0 || cellHeight <= 0 {
+		var maxWidth, maxHeight int
+		for _, img := range loaded {
+			if img.width > maxWidth {
+				maxWidth = img.width
+			}
+			if img.height > maxHeight {
+				maxHeight = img.height
+			}
+		}
+		if cellWidth <= 0 {
+			cellWidth = maxWidth
+		}
+		if cellHeight <= 0 {
+			cellHeight = maxHeight
+		}
+	}
+	if cellWidth == 0 || cellHeight == 0 {
+		i := &Image{}
+		i.addError(ErrNoImages, true)
+		return i
+	}
+
+	i := Canvas(columns*cellWidth+(columns-1)*opt.Spacing, rows*cellHeight+(rows-1)*opt.Spacing, opt.Background)
+	for n, img := range loaded {
+		cell := img.Clone().fit(cellWidth, cellHeight, fit, opt.Gravity)
+		if cell.Error != nil {
+			i.addError(cell.Error, true)
+			return i
+		}
+
+		offset := gravityOffset(opt.Gravity, image.Pt(cellWidth-cell.width, cellHeight-cell.height))
+		x := (n%columns)*(cellWidth+opt.Spacing) + offset.X
+		y := (n/columns)*(cellHeight+opt.Spacing) + offset.Y
+		i.Insert(cell, x, y)
+	}
+
+	return i
+}
+
+// fit scales the image to a width x height box according to fit, and crops the parts outside of the box.
+func (i *Image) fit(width, height int, fit Fit, gravity Gravity) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if i.width > 0 && i.height > 0 {
+		scaleX, scaleY := float64(width)/float64(i.width), float64(height)/float64(i.height)
+		switch fit {
+		case FitContain, FitCover:
+			scale := math.Min(scaleX, scaleY)
+			if fit == FitCover {
+				scale = math.Max(scaleX, scaleY)
+			}
+			i.Resize(int(math.Max(1, math.Round(float64(i.width)*scale))), int(math.Max(1, math.Round(float64(i.height)*scale))))
+		case FitFill:
+			i.Resize(width, height)
+		}
+	}
+
+	return i.CropGravity(width, height, gravity)
+}
+
+// loadAll loads the images, and returns the first error.
+func loadAll(images []interface{}) ([]*Image, error) {
+	if len(images) == 0 {
+		return nil, ErrNoImages
+	}
+
+	loaded := make([]*Image, len(images))
+	for n, source := range images {
+		loaded[n] = Load(source)
+		if loaded[n].Error != nil {
+			return nil, loaded[n].Error
+		}
+	}
+	return loaded, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"image"
 	"image/color"
+	"image/draw"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -199,11 +200,7 @@ func Canvas(width, height int, fillColor ...color.Color) *Image {
 	}
 
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
-	for x := 0; x < img.Bounds().Dx(); x++ {
-		for y := 0; y < img.Bounds().Dy(); y++ {
-			img.Set(x, y, c)
-		}
-	}
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
 
 	return &Image{
 		image:     img,
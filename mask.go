@@ -0,0 +1,217 @@
+package imgo
+
+import (
+	"github.com/nfnt/resize"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Mask applies a mask to the image, making it transparent where the mask is transparent or dark.
+// source can be a file path, a URL, a base64 encoded string, an *os.File, an image.Image,
+// a byte slice or an *Image, it is stretched to the size of the image.
+// mode is how the mask is read, default is MaskAuto, which uses the luminance of grayscale and
+// opaque masks, and the alpha channel of other masks.
+func (i *Image) Mask(source interface{}, mode ...MaskMode) *Image {
+	mask, err := loadMask(source)
+	if err != nil {
+		i.addError(err, true)
+		return i
+	}
+	if i.Error != nil {
+		return i
+	}
+
+	m := MaskAuto
+	if len(mode) > 0 {
+		m = mode[0]
+	}
+
+	i.applyCoverage(coverageOf(mask, m, i.image.Bounds()))
+	return i
+}
+
+// loadMask returns the image of a mask source, which can be anything Load accepts, an image.Image
+// of any type or an *Image.
+func loadMask(source interface{}) (image.Image, error) {
+	switch src := source.(type) {
+	case *Image:
+		return src.image, src.Error
+	case image.Image:
+		return src, nil
+	default:
+		loaded := Load(source)
+		return loaded.image, loaded.Error
+	}
+}
+
+// coverageOf returns the coverage of mask stretched to bounds, read with mode.
+func coverageOf(mask image.Image, mode MaskMode, bounds image.Rectangle) *image.Alpha {
+	if mode == MaskAuto {
+		mode = maskModeOf(mask)
+	}
+
+	if mask.Bounds().Dx() != bounds.Dx() || mask.Bounds().Dy() != bounds.Dy() {
+		mask = resize.Resize(uint(bounds.Dx()), uint(bounds.Dy()), mask, resize.Bilinear)
+	}
+
+	coverage := image.NewAlpha(bounds)
+	offset := mask.Bounds().Min.Sub(bounds.Min)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := mask.At(x+offset.X, y+offset.Y).RGBA()
+			value := a
+			if mode == MaskLuminance {
+				// the luminance of the color over black, so transparent areas mask the image out
+				value = (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+			}
+			coverage.Pix[coverage.PixOffset(x, y)] = uint8(value >> 8)
+		}
+	}
+	return coverage
+}
+
+// CircleMask crops the image to a centered square and makes it a circle, for round avatars.
+func (i *Image) CircleMask() *Image {
+	return i.squareMask(func(center Point, radius float64) []Point {
+		return circlePolygon(center, radius)
+	})
+}
+
+// SquircleMask crops the image to a centered square and makes it a squircle, a square with
+// smoothly rounded sides like app icons.
+func (i *Image) SquircleMask() *Image {
+	return i.squareMask(func(center Point, radius float64) []Point {
+		// the superellipse |x|^4 + |y|^4 = radius^4
+		n := arcSegments(radius, 2*math.Pi) * 2
+		polygon := make([]Point, n)
+		for k := range polygon {
+			sin, cos := math.Sincos(2 * math.Pi * float64(k) / float64(n))
+			x := math.Copysign(math.Sqrt(math.Abs(cos)), cos)
+			y := math.Copysign(math.Sqrt(math.Abs(sin)), sin)
+			polygon[k] = Pt(center.X+radius*x, center.Y+radius*y)
+		}
+		return polygon
+	})
+}
+
+// HexagonMask crops the image to a centered square and makes it a hexagon with a vertex at the top.
+func (i *Image) HexagonMask() *Image {
+	return i.squareMask(func(center Point, radius float64) []Point {
+		return starPolygon(center, 3, radius, radius)
+	})
+}
+
+// StarMask crops the image to a centered square and makes it a star with given number of points.
+// innerRatio is the radius of the inner vertices relative to the outer ones, default is 0.5.
+// Fewer than 2 points adds ErrInvalidStarPoints.
+func (i *Image) StarMask(points int, innerRatio ...float64) *Image {
+	if i.Error != nil {
+		return i
+	}
+	if points < 2 {
+		i.addError(ErrInvalidStarPoints)
+		return i
+	}
+
+	ratio := 0.5
+	if len(innerRatio) > 0 {
+		ratio = innerRatio[0]
+	}
+
+	return i.squareMask(func(center Point, radius float64) []Point {
+		return starPolygon(center, points, radius, radius*ratio)
+	})
+}
+
+// PathMask makes the image transparent outside of the path, in image coordinates.
+// rule is the fill rule of the path, default is NonZero.
+func (i *Image) PathMask(path *Path, rule ...FillRule) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	r := NonZero
+	if len(rule) > 0 {
+		r = rule[0]
+	}
+
+	i.applyCoverage(rasterize(path.polygons(), r, i.image.Bounds(), true))
+	return i
+}
+
+// squareMask crops the image to its largest centered square, and masks it with the shape
+// returned by outline, which is given the center and radius of the square.
+func (i *Image) squareMask(outline func(center Point, radius float64) []Point) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	size := i.width
+	if i.height < size {
+		size = i.height
+	}
+	if size == 0 {
+		return i
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	min := i.image.Bounds().Min.Add(image.Pt((i.width-size)/2, (i.height-size)/2))
+	draw.Draw(dst, dst.Bounds(), i.image, min, draw.Src)
+	i.image = dst
+	i.width = size
+	i.height = size
+
+	half := float64(size) / 2
+	i.applyCoverage(rasterize([][]Point{outline(Pt(half, half), half)}, NonZero, dst.Bounds(), true))
+	return i
+}
+
+// applyCoverage multiplies the pixels of the image by the coverage of mask.
+// Pixels outside of the mask become transparent.
+func (i *Image) applyCoverage(mask *image.Alpha) {
+	dst := i.image
+	b := dst.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			a := uint32(alphaAt(mask, x, y))
+			if a == 0xff {
+				continue
+			}
+			p := dst.Pix[dst.PixOffset(x, y):]
+			for c := 0; c < 4; c++ {
+				p[c] = uint8((uint32(p[c])*a + 127) / 255)
+			}
+		}
+	}
+}
+
+// maskModeOf returns MaskLuminance for grayscale and opaque masks, and MaskAlpha for other masks.
+func maskModeOf(mask image.Image) MaskMode {
+	switch mask.(type) {
+	case *image.Alpha, *image.Alpha16:
+		return MaskAlpha
+	case *image.Gray, *image.Gray16:
+		return MaskLuminance
+	}
+
+	if opaque, ok := mask.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return MaskLuminance
+	}
+	return MaskAlpha
+}
+
+// starPolygon returns the polygon of a star with given number of points, alternating between
+// the outer and inner radius, with the first point at the top.
+func starPolygon(center Point, points int, outer, inner float64) []Point {
+	polygon := make([]Point, 2*points)
+	for k := range polygon {
+		r := outer
+		if k%2 == 1 {
+			r = inner
+		}
+		sin, cos := math.Sincos(math.Pi*float64(k)/float64(points) - math.Pi/2)
+		polygon[k] = Pt(center.X+r*cos, center.Y+r*sin)
+	}
+	return polygon
+}
@@ -0,0 +1,101 @@
+package imgo
+
+import (
+	"math"
+)
+
+// Matrix is a 2D affine transformation matrix
+//
+//	| A C E |
+//	| B D F |
+//	| 0 0 1 |
+//
+// that maps (x, y) to (A*x + C*y + E, B*x + D*y + F), in the same order as the arguments of
+// the HTML canvas setTransform method.
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the identity matrix.
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Multiply returns the matrix that applies n first and then m.
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+// Translate returns the matrix that translates by (x, y) before applying m.
+func (m Matrix) Translate(x, y float64) Matrix {
+	return m.Multiply(Matrix{A: 1, D: 1, E: x, F: y})
+}
+
+// Scale returns the matrix that scales by (sx, sy) before applying m.
+func (m Matrix) Scale(sx, sy float64) Matrix {
+	return m.Multiply(Matrix{A: sx, D: sy})
+}
+
+// Rotate returns the matrix that rotates clockwise by angle in degrees before applying m.
+func (m Matrix) Rotate(angle float64) Matrix {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return m.Multiply(Matrix{A: cos, B: sin, C: -sin, D: cos})
+}
+
+// Skew returns the matrix that skews by the angles ax along the x-axis and ay along the y-axis,
+// in degrees, before applying m.
+func (m Matrix) Skew(ax, ay float64) Matrix {
+	return m.Multiply(Matrix{A: 1, B: math.Tan(ay * math.Pi / 180), C: math.Tan(ax * math.Pi / 180), D: 1})
+}
+
+// Determinant returns the determinant of the linear part of the matrix.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Invert returns the inverse of the matrix, and false if the matrix is not invertible.
+func (m Matrix) Invert() (Matrix, bool) {
+	det := m.Determinant()
+	if det == 0 || math.IsNaN(det) {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// Apply returns the point p transformed by the matrix.
+func (m Matrix) Apply(p Point) Point {
+	return Point{X: m.A*p.X + m.C*p.Y + m.E, Y: m.B*p.X + m.D*p.Y + m.F}
+}
+
+// isSimilarity reports whether the matrix only translates, rotates, flips and scales uniformly,
+// and returns the scale factor.
+func (m Matrix) isSimilarity() (float64, bool) {
+	const epsilon = 1e-9
+	sx, sy := math.Hypot(m.A, m.B), math.Hypot(m.C, m.D)
+	orthogonal := math.Abs(m.A*m.C+m.B*m.D) < epsilon*sx*sy
+	return sx, orthogonal && math.Abs(sx-sy) < epsilon*sx
+}
+
+// applyPolygons transforms the points of the polygons in place.
+func (m Matrix) applyPolygons(polygons [][]Point) [][]Point {
+	for _, polygon := range polygons {
+		for n := range polygon {
+			polygon[n] = m.Apply(polygon[n])
+		}
+	}
+	return polygons
+}
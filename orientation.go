@@ -0,0 +1,100 @@
+package imgo
+
+import (
+	"image"
+)
+
+// orientation is one of the 8 ways to turn an image by quarter turns and mirror it.
+// It maps the pixel (x, y) of the source to (xx*x + xy*y, yx*x + yy*y) in the destination,
+// moved back inside the destination along the axes with a negative factor.
+type orientation struct {
+	xx, xy, yx, yy int
+}
+
+var (
+	rotate90       = orientation{xy: -1, yx: 1}
+	rotate180      = orientation{xx: -1, yy: -1}
+	rotate270      = orientation{xy: 1, yx: -1}
+	flipHorizontal = orientation{xx: -1, yy: 1}
+	flipVertical   = orientation{xx: 1, yy: -1}
+	transpose      = orientation{xy: 1, yx: 1}
+	transverse     = orientation{xy: -1, yx: -1}
+
+	// rotations are the clockwise rotations by 0, 90, 180 and 270 degrees
+	rotations = [4]orientation{{xx: 1, yy: 1}, rotate90, rotate180, rotate270}
+)
+
+// Rotate90 rotates the image clockwise by 90 degrees, without interpolation.
+func (i *Image) Rotate90() *Image {
+	return i.reorient(rotate90)
+}
+
+// Rotate180 rotates the image by 180 degrees, without interpolation.
+func (i *Image) Rotate180() *Image {
+	return i.reorient(rotate180)
+}
+
+// Rotate270 rotates the image clockwise by 270 degrees, without interpolation.
+func (i *Image) Rotate270() *Image {
+	return i.reorient(rotate270)
+}
+
+// Transpose mirrors the image along its top-left to bottom-right diagonal.
+func (i *Image) Transpose() *Image {
+	return i.reorient(transpose)
+}
+
+// Transverse mirrors the image along its top-right to bottom-left diagonal.
+func (i *Image) Transverse() *Image {
+	return i.reorient(transverse)
+}
+
+// reorient moves the pixels of the image to the orientation o.
+// Flips are done in place, other orientations swap the width and the height or move every pixel.
+func (i *Image) reorient(o orientation) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	switch o {
+	case rotations[0]:
+		return i
+	case flipHorizontal:
+		i.flipHorizontally()
+		return i
+	case flipVertical:
+		i.flipVertically()
+		return i
+	}
+
+	src := i.image
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if o.xx == 0 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	// the destination of the pixel (0, 0)
+	ox, oy := 0, 0
+	if o.xx < 0 || o.xy < 0 {
+		ox = dw - 1
+	}
+	if o.yx < 0 || o.yy < 0 {
+		oy = dh - 1
+	}
+
+	for y := 0; y < h; y++ {
+		row := src.Pix[src.PixOffset(b.Min.X, b.Min.Y+y):]
+		for x := 0; x < w; x++ {
+			dx, dy := ox+o.xx*x+o.xy*y, oy+o.yx*x+o.yy*y
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], row[4*x:4*x+4])
+		}
+	}
+
+	i.image = dst
+	i.width = dw
+	i.height = dh
+	return i
+}
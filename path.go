@@ -0,0 +1,328 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// pathOp is the operation of a path segment.
+type pathOp int
+
+const (
+	pathMoveTo pathOp = iota
+	pathLineTo
+	pathQuadTo
+	pathCubicTo
+	pathClose
+)
+
+// pathSegment is a segment of a path, its points are the control points followed by the end point.
+type pathSegment struct {
+	op     pathOp
+	points []Point
+}
+
+// Path is a vector path made of lines, quadratic and cubic Bézier curves and elliptical arcs.
+// Arcs are stored as cubic Bézier curves. A path is built by chaining its methods:
+//
+//	path := imgo.NewPath().MoveTo(10, 10).LineTo(100, 10).QuadTo(150, 50, 100, 100).Close()
+type Path struct {
+	segments []pathSegment
+	start    Point // start of the current subpath
+	current  Point // current point
+	started  bool  // whether there is a current point
+}
+
+// NewPath creates a new empty path.
+func NewPath() *Path {
+	return &Path{}
+}
+
+// MoveTo starts a new subpath at (x, y).
+func (p *Path) MoveTo(x, y float64) *Path {
+	p.segments = append(p.segments, pathSegment{pathMoveTo, []Point{Pt(x, y)}})
+	p.start = Pt(x, y)
+	p.current = p.start
+	p.started = true
+	return p
+}
+
+// LineTo adds a line from the current point to (x, y).
+func (p *Path) LineTo(x, y float64) *Path {
+	if !p.started {
+		return p.MoveTo(x, y)
+	}
+	p.segments = append(p.segments, pathSegment{pathLineTo, []Point{Pt(x, y)}})
+	p.current = Pt(x, y)
+	return p
+}
+
+// QuadTo adds a quadratic Bézier curve from the current point to (x, y) with control point (cx, cy).
+func (p *Path) QuadTo(cx, cy, x, y float64) *Path {
+	if !p.started {
+		p.MoveTo(cx, cy)
+	}
+	p.segments = append(p.segments, pathSegment{pathQuadTo, []Point{Pt(cx, cy), Pt(x, y)}})
+	p.current = Pt(x, y)
+	return p
+}
+
+// CubicTo adds a cubic Bézier curve from the current point to (x, y) with control points
+// (c1x, c1y) and (c2x, c2y).
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float64) *Path {
+	if !p.started {
+		p.MoveTo(c1x, c1y)
+	}
+	p.segments = append(p.segments, pathSegment{pathCubicTo, []Point{Pt(c1x, c1y), Pt(c2x, c2y), Pt(x, y)}})
+	p.current = Pt(x, y)
+	return p
+}
+
+// ArcTo adds an elliptical arc from the current point to (x, y), like the SVG arc command.
+// rx and ry are the radii of the ellipse, rotation is the angle of its x-axis in degrees.
+// largeArc chooses the arc longer than 180 degrees, and sweep the arc drawn clockwise.
+// Radii that are too small to reach (x, y) are scaled up.
+func (p *Path) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) *Path {
+	if !p.started {
+		p.MoveTo(p.current.X, p.current.Y)
+	}
+	from, to := p.current, Pt(x, y)
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if from == to {
+		return p
+	}
+	if rx == 0 || ry == 0 {
+		return p.LineTo(x, y)
+	}
+
+	// convert from endpoint to center parameterization, as in the SVG implementation notes
+	phi := rotation * math.Pi / 180
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	dx, dy := (from.X-to.X)/2, (from.Y-to.Y)/2
+	x1 := cos*dx + sin*dy
+	y1 := -sin*dx + cos*dy
+
+	if lambda := x1*x1/(rx*rx) + y1*y1/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	k := math.Sqrt(math.Max(0, num/den))
+	if largeArc == sweep {
+		k = -k
+	}
+	cx1, cy1 := k*rx*y1/ry, -k*ry*x1/rx
+	cx := cos*cx1 - sin*cy1 + (from.X+to.X)/2
+	cy := sin*cx1 + cos*cy1 + (from.Y+to.Y)/2
+
+	angle := func(ux, uy, vx, vy float64) float64 {
+		return math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	}
+	theta := angle(1, 0, (x1-cx1)/rx, (y1-cy1)/ry)
+	delta := angle((x1-cx1)/rx, (y1-cy1)/ry, (-x1-cx1)/rx, (-y1-cy1)/ry)
+	if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	} else if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	}
+
+	// split the arc in pieces of at most 90 degrees, each approximated by a cubic Bézier curve
+	pieces := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	step := delta / float64(pieces)
+	t := 4.0 / 3.0 * math.Tan(step/4)
+	point := func(a float64) (Point, Point) {
+		ex, ey := rx*math.Cos(a), ry*math.Sin(a)
+		tx, ty := -rx*math.Sin(a), ry*math.Cos(a)
+		return Pt(cos*ex-sin*ey+cx, sin*ex+cos*ey+cy), Pt(cos*tx-sin*ty, sin*tx+cos*ty)
+	}
+	for n := 0; n < pieces; n++ {
+		a0 := theta + step*float64(n)
+		a1 := a0 + step
+		p0, d0 := point(a0)
+		p1, d1 := point(a1)
+		if n == pieces-1 {
+			p1 = to
+		}
+		c1 := p0.Add(d0.Mul(t))
+		c2 := p1.Sub(d1.Mul(t))
+		p.CubicTo(c1.X, c1.Y, c2.X, c2.Y, p1.X, p1.Y)
+	}
+
+	return p
+}
+
+// Close closes the current subpath with a line back to its start.
+func (p *Path) Close() *Path {
+	if !p.started {
+		return p
+	}
+	p.segments = append(p.segments, pathSegment{pathClose, nil})
+	p.current = p.start
+	return p
+}
+
+// Transform returns a copy of the path with all its points transformed by the matrix.
+func (p *Path) Transform(m Matrix) *Path {
+	t := &Path{
+		segments: make([]pathSegment, len(p.segments)),
+		start:    m.Apply(p.start),
+		current:  m.Apply(p.current),
+		started:  p.started,
+	}
+	for n, s := range p.segments {
+		points := make([]Point, len(s.points))
+		for k, point := range s.points {
+			points[k] = m.Apply(point)
+		}
+		t.segments[n] = pathSegment{s.op, points}
+	}
+	return t
+}
+
+// CurrentPoint returns the current point of the path.
+func (p *Path) CurrentPoint() Point {
+	return p.current
+}
+
+// polyline is a flattened subpath.
+type polyline struct {
+	points []Point
+	closed bool
+}
+
+// flatten approximates the path with polylines, one for each subpath.
+func (p *Path) flatten() []polyline {
+	var lines []polyline
+	var current []Point
+	var last Point
+
+	flush := func(closed bool) {
+		if len(current) > 0 {
+			lines = append(lines, polyline{current, closed})
+		}
+		current = nil
+	}
+
+	for _, s := range p.segments {
+		switch s.op {
+		case pathMoveTo:
+			if len(current) > 1 {
+				flush(false)
+			}
+			last = s.points[0]
+			current = []Point{last}
+		case pathLineTo:
+			current = append(current, s.points[0])
+			last = s.points[0]
+		case pathQuadTo:
+			current = append(current, flattenQuad(last, s.points[0], s.points[1])...)
+			last = s.points[1]
+		case pathCubicTo:
+			current = append(current, flattenCubic(last, s.points[0], s.points[1], s.points[2])...)
+			last = s.points[2]
+		case pathClose:
+			if len(current) > 0 {
+				last = current[0]
+				flush(true)
+				current = []Point{last}
+			}
+		}
+	}
+	if len(current) > 1 {
+		flush(false)
+	}
+
+	return lines
+}
+
+// polygons returns the flattened subpaths of the path as polygons to fill.
+func (p *Path) polygons() [][]Point {
+	var polygons [][]Point
+	for _, line := range p.flatten() {
+		if len(line.points) > 2 {
+			polygons = append(polygons, line.points)
+		}
+	}
+	return polygons
+}
+
+// strokePolygons returns the polygons covering the stroke of the path.
+func (p *Path) strokePolygons(options StrokeOptions) [][]Point {
+	var polygons [][]Point
+	for _, line := range p.flatten() {
+		polygons = append(polygons, strokePolyline(line.points, line.closed, options)...)
+	}
+	return polygons
+}
+
+// flattenQuad returns the points approximating a quadratic Bézier curve, without its start point.
+// The number of segments comes from Wang's formula.
+func flattenQuad(p0, p1, p2 Point) []Point {
+	dd := p0.Sub(p1.Mul(2)).Add(p2)
+	n := int(math.Ceil(math.Sqrt(math.Hypot(dd.X, dd.Y) / (4 * curveTolerance))))
+	if n < 1 {
+		n = 1
+	}
+	points := make([]Point, n)
+	for k := 1; k <= n; k++ {
+		t := float64(k) / float64(n)
+		u := 1 - t
+		points[k-1] = p0.Mul(u * u).Add(p1.Mul(2 * u * t)).Add(p2.Mul(t * t))
+	}
+	return points
+}
+
+// flattenCubic returns the points approximating a cubic Bézier curve, without its start point.
+// The number of segments comes from Wang's formula.
+func flattenCubic(p0, p1, p2, p3 Point) []Point {
+	d1 := p0.Sub(p1.Mul(2)).Add(p2)
+	d2 := p1.Sub(p2.Mul(2)).Add(p3)
+	m := math.Max(math.Hypot(d1.X, d1.Y), math.Hypot(d2.X, d2.Y))
+	n := int(math.Ceil(math.Sqrt(3 * m / (4 * curveTolerance))))
+	if n < 1 {
+		n = 1
+	}
+	points := make([]Point, n)
+	for k := 1; k <= n; k++ {
+		t := float64(k) / float64(n)
+		u := 1 - t
+		points[k-1] = p0.Mul(u * u * u).Add(p1.Mul(3 * u * u * t)).Add(p2.Mul(3 * u * t * t)).Add(p3.Mul(t * t * t))
+	}
+	return points
+}
+
+// FillPath fills the path with given color. Subpaths are implicitly closed.
+// rule is the fill rule that decides which areas are inside the path, default is NonZero.
+func (i *Image) FillPath(path *Path, c color.Color, rule ...FillRule) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	r := NonZero
+	if len(rule) > 0 {
+		r = rule[0]
+	}
+
+	i.fillPolygons(path.polygons(), r, image.NewUniform(c), true)
+
+	return i
+}
+
+// StrokePath draws the outline of the path with given color.
+// The line is 1 pixel wide with butt caps and miter joins, unless options are given.
+func (i *Image) StrokePath(path *Path, c color.Color, options ...StrokeOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	var opt StrokeOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	i.fillPolygons(path.strokePolygons(opt), NonZero, image.NewUniform(c), !opt.Aliased)
+
+	return i
+}
@@ -4,25 +4,91 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"sync"
 )
 
-// BorderRadius draws rounded corners on the image with given radius.
-func (i *Image) BorderRadius(radius float64) *Image {
-	if radius > float64(i.width/2) || radius > float64(i.height/2) {
+// BorderRadiusOptions is the options of BorderRadius.
+type BorderRadiusOptions struct {
+	Radii       CornerRadii // radius of each corner, overrides the radius argument when not zero
+	RadiiY      CornerRadii // vertical radius of each corner for elliptical corners, default is the same as the horizontal radius
+	BorderWidth float64     // width of the border drawn inside the rounded edge, 0 means no border
+	BorderColor color.Color // color of the border, default is black
+	Background  color.Color // color of the area cut out of the corners, default is transparent, useful for JPEG
+}
+
+// BorderRadius rounds the corners of the image with given radius, with anti-aliased edges.
+// Radii too large for the image are scaled down like CSS border-radius.
+func (i *Image) BorderRadius(radius float64, options ...BorderRadiusOptions) *Image {
+	if i.Error != nil {
 		return i
 	}
 
-	c := Radius{p: image.Point{X: i.width, Y: i.height}, r: int(radius)}
-	dst := image.NewRGBA(i.image.Bounds())
-	draw.DrawMask(dst, dst.Bounds(), i.image, image.Point{}, &c, image.Point{}, draw.Over)
+	var opt BorderRadiusOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	rx := UniformRadii(radius)
+	if opt.Radii != (CornerRadii{}) {
+		rx = opt.Radii
+	}
+	ry := rx
+	if opt.RadiiY != (CornerRadii{}) {
+		ry = opt.RadiiY
+	}
+
+	bounds := i.image.Bounds()
+	min, max := Pt(float64(bounds.Min.X), float64(bounds.Min.Y)), Pt(float64(bounds.Max.X), float64(bounds.Max.Y))
+	rx, ry = fitRadii(max.X-min.X, max.Y-min.Y, rx, ry)
+	outline := roundedRectPolygon(min, max, rx, ry)
+
+	mask := rasterize([][]Point{outline}, NonZero, bounds, true)
+	dst := image.NewRGBA(bounds)
+	draw.DrawMask(dst, bounds, i.image, bounds.Min, mask, bounds.Min, draw.Src)
+
+	// the background fills the area outside of the outline only, so that it doesn't show through the image
+	if opt.Background != nil {
+		bg := color.RGBAModel.Convert(opt.Background).(color.RGBA)
+		background := [4]uint32{uint32(bg.R), uint32(bg.G), uint32(bg.B), uint32(bg.A)}
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			p := dst.Pix[dst.PixOffset(bounds.Min.X, y):]
+			m := mask.Pix[mask.PixOffset(bounds.Min.X, y):]
+			for x := 0; x < bounds.Dx(); x++ {
+				outside := 255 - uint32(m[x])
+				for c := 0; c < 4; c++ {
+					p[4*x+c] += uint8((background[c]*outside + 127) / 255)
+				}
+			}
+		}
+	}
+
+	// the inner edge of the border follows the outer edge, with radii reduced by the border width
+	if w := opt.BorderWidth; w > 0 {
+		inner := Pt(w, w)
+		shrink := func(r CornerRadii) CornerRadii {
+			return CornerRadii{r.TopLeft - w, r.TopRight - w, r.BottomRight - w, r.BottomLeft - w}
+		}
+		border := [][]Point{outline}
+		if max.X-min.X > 2*w && max.Y-min.Y > 2*w {
+			border = append(border, roundedRectPolygon(min.Add(inner), max.Sub(inner), shrink(rx), shrink(ry)))
+		}
+		mask = rasterize(border, EvenOdd, bounds, true)
+		draw.DrawMask(dst, mask.Bounds(), image.NewUniform(colorOrBlack(opt.BorderColor)), mask.Bounds().Min, mask, mask.Bounds().Min, draw.Over)
+	}
 
 	i.image = dst
 	return i
 }
 
+// Radius is the mask of an image of size p with rounded corners of radius r, anti-aliased.
+//
+// Deprecated: Use BorderRadius, which also supports per-corner radii and borders.
 type Radius struct {
 	p image.Point // the right-bottom Point of the image
 	r int         // radius
+
+	once sync.Once
+	mask *image.Alpha
 }
 
 func (c *Radius) ColorModel() color.Model {
@@ -34,35 +100,11 @@ func (c *Radius) Bounds() image.Rectangle {
 }
 
 func (c *Radius) At(x, y int) color.Color {
-	var xx, yy, rr float64
-	var inArea bool
-
-	// left up
-	if x <= c.r && y <= c.r {
-		xx, yy, rr = float64(c.r-x)+0.5, float64(y-c.r)+0.5, float64(c.r)
-		inArea = true
-	}
-
-	// right up
-	if x >= (c.p.X-c.r) && y <= c.r {
-		xx, yy, rr = float64(x-(c.p.X-c.r))+0.5, float64(y-c.r)+0.5, float64(c.r)
-		inArea = true
-	}
-
-	// left bottom
-	if x <= c.r && y >= (c.p.Y-c.r) {
-		xx, yy, rr = float64(c.r-x)+0.5, float64(y-(c.p.Y-c.r))+0.5, float64(c.r)
-		inArea = true
-	}
-
-	// right bottom
-	if x >= (c.p.X-c.r) && y >= (c.p.Y-c.r) {
-		xx, yy, rr = float64(x-(c.p.X-c.r))+0.5, float64(y-(c.p.Y-c.r))+0.5, float64(c.r)
-		inArea = true
-	}
-
-	if inArea && xx*xx+yy*yy >= rr*rr {
-		return color.Alpha{}
-	}
-	return color.Alpha{A: 255}
+	c.once.Do(func() {
+		bounds := c.Bounds()
+		max := Pt(float64(c.p.X), float64(c.p.Y))
+		rx, ry := fitRadii(max.X, max.Y, UniformRadii(float64(c.r)), UniformRadii(float64(c.r)))
+		c.mask = rasterize([][]Point{roundedRectPolygon(Point{}, max, rx, ry)}, NonZero, bounds, true)
+	})
+	return c.mask.At(x, y)
 }
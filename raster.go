@@ -0,0 +1,177 @@
+package imgo
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+)
+
+// Point is a point in continuous image coordinates, where the pixel (x, y) covers the square
+// from (x, y) to (x+1, y+1), so its center is (x+0.5, y+0.5).
+type Point struct {
+	X, Y float64
+}
+
+// Pt is shorthand for Point{X: x, Y: y}.
+func Pt(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns the vector p+q.
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the vector p-q.
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul returns the vector p*k.
+func (p Point) Mul(k float64) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// subSamples is the number of sub scanlines sampled per pixel row when anti-aliasing.
+const subSamples = 16
+
+// edge is a non horizontal polygon edge, from top to bottom.
+type edge struct {
+	x0, y0, x1, y1 float64
+	dir            int // winding direction, 1 for downwards and -1 for upwards
+}
+
+// rasterize returns the coverage mask of polygons clipped to bounds. Polygons are implicitly
+// closed, and overlapping polygons are combined with the fill rule.
+// When antiAlias is false, pixels are either fully covered or not covered at all.
+func rasterize(polygons [][]Point, rule FillRule, bounds image.Rectangle, antiAlias bool) *image.Alpha {
+	// collect the edges and their bounding box
+	var edges []edge
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, polygon := range polygons {
+		for n := range polygon {
+			p, q := polygon[n], polygon[(n+1)%len(polygon)]
+			if p.Y == q.Y || math.IsNaN(p.X+p.Y+q.X+q.Y) {
+				continue
+			}
+			e := edge{p.X, p.Y, q.X, q.Y, 1}
+			if p.Y > q.Y {
+				e = edge{q.X, q.Y, p.X, p.Y, -1}
+			}
+			edges = append(edges, e)
+			minY, maxY = math.Min(minY, e.y0), math.Max(maxY, e.y1)
+		}
+	}
+
+	// clip the mask to the rows the polygons cover
+	top := int(math.Max(float64(bounds.Min.Y), math.Floor(minY)))
+	bottom := int(math.Min(float64(bounds.Max.Y), math.Ceil(maxY)))
+	if len(edges) == 0 || top >= bottom {
+		return image.NewAlpha(image.Rectangle{})
+	}
+	mask := image.NewAlpha(image.Rect(bounds.Min.X, top, bounds.Max.X, bottom))
+	width := bounds.Dx()
+
+	sort.Slice(edges, func(a, b int) bool { return edges[a].y0 < edges[b].y0 })
+
+	samples := subSamples
+	if !antiAlias {
+		samples = 1
+	}
+	weight := 1 / float64(samples)
+
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	var active []edge
+	var crossings []crossing
+	cover := make([]float64, width+1) // partial coverage of each pixel
+	runs := make([]float64, width+2)  // differences of full coverage, accumulated along the row
+	next := 0
+
+	for y := top; y < bottom; y++ {
+		for s := 0; s < samples; s++ {
+			sy := float64(y) + (float64(s)+0.5)*weight
+
+			// update the active edges for this sub scanline
+			for next < len(edges) && edges[next].y0 <= sy {
+				active = append(active, edges[next])
+				next++
+			}
+			crossings = crossings[:0]
+			kept := active[:0]
+			for _, e := range active {
+				if e.y1 <= sy {
+					continue
+				}
+				kept = append(kept, e)
+				if e.y0 <= sy {
+					x := e.x0 + (sy-e.y0)*(e.x1-e.x0)/(e.y1-e.y0)
+					crossings = append(crossings, crossing{x - float64(bounds.Min.X), e.dir})
+				}
+			}
+			active = kept
+			sort.Slice(crossings, func(a, b int) bool { return crossings[a].x < crossings[b].x })
+
+			// fill the spans that are inside according to the fill rule
+			winding := 0
+			for n := 0; n+1 < len(crossings); n++ {
+				winding += crossings[n].dir
+				inside := winding != 0
+				if rule == EvenOdd {
+					inside = winding%2 != 0
+				}
+				if inside {
+					addSpan(cover, runs, crossings[n].x, crossings[n+1].x, weight)
+				}
+			}
+		}
+
+		// accumulate the coverage of the row into the mask
+		run := 0.0
+		row := mask.Pix[mask.PixOffset(bounds.Min.X, y):]
+		for x := 0; x < width; x++ {
+			run += runs[x]
+			c := math.Min(1, cover[x]+run)
+			if !antiAlias {
+				c = math.Round(c)
+			}
+			row[x] = uint8(c*255 + 0.5)
+			cover[x], runs[x] = 0, 0
+		}
+		runs[width], runs[width+1] = 0, 0
+	}
+
+	return mask
+}
+
+// addSpan adds the coverage of the horizontal span from x0 to x1 with given weight. Fully covered
+// pixels are recorded as differences in runs, and partially covered ones in cover.
+func addSpan(cover, runs []float64, x0, x1, weight float64) {
+	width := float64(len(cover) - 1)
+	x0, x1 = math.Max(0, x0), math.Min(width, x1)
+	if x1 <= x0 {
+		return
+	}
+
+	i0, i1 := int(x0), int(x1)
+	if i0 == i1 {
+		cover[i0] += (x1 - x0) * weight
+		return
+	}
+	cover[i0] += (float64(i0+1) - x0) * weight
+	runs[i0+1] += weight
+	runs[i1] -= weight
+	cover[i1] += (x1 - float64(i1)) * weight
+}
+
+// fillPolygons fills polygons on the image with src through the coverage mask.
+func (i *Image) fillPolygons(polygons [][]Point, rule FillRule, src image.Image, antiAlias bool) {
+	mask := rasterize(polygons, rule, i.image.Bounds(), antiAlias)
+	if mask.Bounds().Empty() {
+		return
+	}
+	draw.DrawMask(i.image, mask.Bounds(), src, mask.Bounds().Min, mask, mask.Bounds().Min, draw.Over)
+}
@@ -0,0 +1,58 @@
+package imgo
+
+import (
+	"image"
+	"testing"
+)
+
+func TestRasterizeRectangleEdges(t *testing.T) {
+	tests := []struct {
+		name      string
+		x0, x1    float64
+		antiAlias bool
+		want      [4]uint8
+	}{
+		{"pixel aligned", 1, 3, true, [4]uint8{0, 255, 255, 0}},
+		{"half pixel left edge", 0.5, 3, true, [4]uint8{128, 255, 255, 0}},
+		{"half pixel right edge", 1, 2.5, true, [4]uint8{0, 255, 128, 0}},
+		{"quarter pixel edges", 0.25, 3.75, true, [4]uint8{191, 255, 255, 191}},
+		{"inside a single pixel", 1.25, 1.75, true, [4]uint8{0, 128, 0, 0}},
+		{"half pixel without anti-aliasing", 0.5, 2.4, false, [4]uint8{255, 255, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rect := []Point{Pt(tt.x0, 0), Pt(tt.x1, 0), Pt(tt.x1, 1), Pt(tt.x0, 1)}
+			mask := rasterize([][]Point{rect}, NonZero, image.Rect(0, 0, 4, 1), tt.antiAlias)
+			for x, want := range tt.want {
+				got := mask.AlphaAt(x, 0).A
+				if d := int(got) - int(want); d > 1 || d < -1 {
+					t.Errorf("coverage of pixel %d = %d, want %d", x, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRasterizeFillRules(t *testing.T) {
+	outer := []Point{Pt(0, 0), Pt(4, 0), Pt(4, 4), Pt(0, 4)}
+	inner := []Point{Pt(1, 1), Pt(3, 1), Pt(3, 3), Pt(1, 3)}
+
+	tests := []struct {
+		rule FillRule
+		want uint8
+	}{
+		{NonZero, 255},
+		{EvenOdd, 0},
+	}
+
+	for _, tt := range tests {
+		mask := rasterize([][]Point{outer, inner}, tt.rule, image.Rect(0, 0, 4, 4), true)
+		if got := mask.AlphaAt(2, 2).A; got != tt.want {
+			t.Errorf("rule %v: coverage of the hole = %d, want %d", tt.rule, got, tt.want)
+		}
+		if got := mask.AlphaAt(0, 0).A; got != 255 {
+			t.Errorf("rule %v: coverage of the ring = %d, want 255", tt.rule, got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// sampleBilinear returns the premultiplied color of src at the continuous coordinate (x, y),
+// interpolated between the four nearest pixel centers, with components between 0 and 1.
+// Pixels outside src are transparent, so that edges are smooth.
+func sampleBilinear(src *image.RGBA, x, y float64) (c [4]float64) {
+	fx, fy := x-0.5, y-0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+
+	b := src.Bounds()
+	for dy := 0; dy <= 1; dy++ {
+		py := y0 + dy
+		if py < b.Min.Y || py >= b.Max.Y {
+			continue
+		}
+		wy := 1 - ty
+		if dy == 1 {
+			wy = ty
+		}
+		for dx := 0; dx <= 1; dx++ {
+			px := x0 + dx
+			if px < b.Min.X || px >= b.Max.X {
+				continue
+			}
+			wx := 1 - tx
+			if dx == 1 {
+				wx = tx
+			}
+			w := wx * wy / 255
+			p := src.Pix[src.PixOffset(px, py):]
+			c[0] += float64(p[0]) * w
+			c[1] += float64(p[1]) * w
+			c[2] += float64(p[2]) * w
+			c[3] += float64(p[3]) * w
+		}
+	}
+	return
+}
+
+// sampleNearest returns the premultiplied color of the pixel of src containing (x, y),
+// with components between 0 and 1, or transparent outside of src.
+func sampleNearest(src *image.RGBA, x, y float64) (c [4]float64) {
+	px, py := int(math.Floor(x)), int(math.Floor(y))
+	if !image.Pt(px, py).In(src.Bounds()) {
+		return
+	}
+	p := src.Pix[src.PixOffset(px, py):]
+	return [4]float64{float64(p[0]) / 255, float64(p[1]) / 255, float64(p[2]) / 255, float64(p[3]) / 255}
+}
+
+// sampleBicubic returns the premultiplied color of src at (x, y), interpolated with a Catmull-Rom
+// spline through the 16 nearest pixel centers, with components between 0 and 1.
+// Pixels outside src are transparent, so that edges are smooth.
+func sampleBicubic(src *image.RGBA, x, y float64) (c [4]float64) {
+	fx, fy := x-0.5, y-0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+	wx, wy := catmullRom(tx), catmullRom(ty)
+
+	b := src.Bounds()
+	for dy := 0; dy < 4; dy++ {
+		py := y0 + dy - 1
+		if py < b.Min.Y || py >= b.Max.Y {
+			continue
+		}
+		for dx := 0; dx < 4; dx++ {
+			px := x0 + dx - 1
+			if px < b.Min.X || px >= b.Max.X {
+				continue
+			}
+			w := wx[dx] * wy[dy] / 255
+			p := src.Pix[src.PixOffset(px, py):]
+			c[0] += float64(p[0]) * w
+			c[1] += float64(p[1]) * w
+			c[2] += float64(p[2]) * w
+			c[3] += float64(p[3]) * w
+		}
+	}
+
+	// the spline overshoots near sharp edges, and colors can't exceed their alpha
+	c[3] = math.Max(0, math.Min(1, c[3]))
+	for n := 0; n < 3; n++ {
+		c[n] = math.Max(0, math.Min(c[3], c[n]))
+	}
+	return
+}
+
+// catmullRom returns the weights of the 4 pixels around a point at fraction t between the 2 middle ones.
+func catmullRom(t float64) [4]float64 {
+	t2, t3 := t*t, t*t*t
+	return [4]float64{
+		(-t3 + 2*t2 - t) / 2,
+		(3*t3 - 5*t2 + 2) / 2,
+		(-3*t3 + 4*t2 + t) / 2,
+		(t3 - t2) / 2,
+	}
+}
+
+// samplerOf returns the sampling function of the interpolation.
+func samplerOf(interpolation Interpolation) func(src *image.RGBA, x, y float64) [4]float64 {
+	switch interpolation {
+	case InterpolationNearest:
+		return sampleNearest
+	case InterpolationBicubic:
+		return sampleBicubic
+	default:
+		return sampleBilinear
+	}
+}
+
+// warp fills dst with src seen through a geometric transform. inverse maps the center of each
+// pixel of dst to a point of src, and returns false for pixels that have no source.
+// The sampled colors are drawn over background, which can be nil for transparent.
+// Rows are warped concurrently.
+func warp(dst, src *image.RGBA, interpolation Interpolation, background color.Color, inverse func(p Point) (Point, bool)) {
+	sample := samplerOf(interpolation)
+
+	var bg [4]float64
+	if background != nil {
+		r, g, b, a := background.RGBA()
+		bg = [4]float64{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff, float64(a) / 0xffff}
+	}
+
+	bounds := dst.Bounds()
+	rows := make(chan int)
+	wg := sync.WaitGroup{}
+	for n := 0; n < runtime.NumCPU(); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := range rows {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					var c [4]float64
+					if p, ok := inverse(Pt(float64(x)+0.5, float64(y)+0.5)); ok {
+						c = sample(src, p.X, p.Y)
+					}
+					out := dst.Pix[dst.PixOffset(x, y):]
+					for k := 0; k < 4; k++ {
+						out[k] = uint8(math.Max(0, math.Min(1, c[k]+bg[k]*(1-c[3])))*255 + 0.5)
+					}
+				}
+			}
+		}()
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		rows <- y
+	}
+	close(rows)
+	wg.Wait()
+}
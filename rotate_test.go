@@ -0,0 +1,159 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// numbered returns a w x h image where each pixel has a distinct color.
+func numbered(w, h int) *Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(10 * x), G: uint8(10 * y), B: 200, A: 255})
+		}
+	}
+	return &Image{image: img, width: w, height: h}
+}
+
+func TestRotateQuarterTurns(t *testing.T) {
+	tests := []struct {
+		name    string
+		angle   float64
+		options RotateOptions
+		// where the source pixel (x, y) of a w x h image lands
+		to func(x, y, w, h int) (int, int)
+	}{
+		{"90", 90, RotateOptions{}, func(x, y, w, h int) (int, int) { return h - 1 - y, x }},
+		{"180", 180, RotateOptions{}, func(x, y, w, h int) (int, int) { return w - 1 - x, h - 1 - y }},
+		{"270", 270, RotateOptions{}, func(x, y, w, h int) (int, int) { return y, w - 1 - x }},
+		{"-90", -90, RotateOptions{}, func(x, y, w, h int) (int, int) { return y, w - 1 - x }},
+		{"450", 450, RotateOptions{}, func(x, y, w, h int) (int, int) { return h - 1 - y, x }},
+		// the size is kept, so the square images below go through warp
+		{"90 keep", 90, RotateOptions{Mode: RotateKeep}, func(x, y, w, h int) (int, int) { return h - 1 - y, x }},
+		{"270 keep", 270, RotateOptions{Mode: RotateKeep}, func(x, y, w, h int) (int, int) { return y, w - 1 - x }},
+		{"180 keep around the center", 180, RotateOptions{Mode: RotateKeep, Center: &Point{X: 2, Y: 2}},
+			func(x, y, w, h int) (int, int) { return w - 1 - x, h - 1 - y }},
+	}
+
+	for _, tt := range tests {
+		for _, interpolation := range []Interpolation{InterpolationBilinear, InterpolationNearest, InterpolationBicubic} {
+			t.Run(tt.name, func(t *testing.T) {
+				w, h := 5, 3
+				if tt.options.Mode == RotateKeep {
+					w, h = 4, 4
+				}
+				src := numbered(w, h)
+				opt := tt.options
+				opt.Interpolation = interpolation
+				dst := numbered(w, h).RotateAngle(tt.angle, opt)
+				if dst.Error != nil {
+					t.Fatal(dst.Error)
+				}
+
+				for y := 0; y < h; y++ {
+					for x := 0; x < w; x++ {
+						dx, dy := tt.to(x, y, w, h)
+						if got, want := dst.image.RGBAAt(dx, dy), src.image.RGBAAt(x, y); got != want {
+							t.Errorf("pixel (%d, %d) moved to (%d, %d) is %v, want %v", x, y, dx, dy, got, want)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestWarpIdentity(t *testing.T) {
+	src := numbered(4, 3)
+	dst := image.NewRGBA(src.image.Bounds())
+	warp(dst, src.image, InterpolationBicubic, nil, func(p Point) (Point, bool) { return p, true })
+
+	for n := range dst.Pix {
+		if dst.Pix[n] != src.image.Pix[n] {
+			t.Fatalf("identity warp changed byte %d from %d to %d", n, src.image.Pix[n], dst.Pix[n])
+		}
+	}
+}
+
+func TestInscribedRect(t *testing.T) {
+	tests := []struct {
+		name          string
+		w, h, angle   float64
+		width, height float64
+	}{
+		{"0", 200, 100, 0, 200, 100},
+		{"90", 200, 100, 90, 100, 200},
+		{"180", 200, 100, 180, 200, 100},
+		{"270", 200, 100, 270, 100, 200},
+		{"square at 45", 100, 100, 45, 100 / math.Sqrt2, 100 / math.Sqrt2},
+		{"thin at 30", 200, 100, 30, 100, 100 / math.Sqrt(3)},
+		{"tall at 30", 100, 200, 30, 100 / math.Sqrt(3), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sin, cos := math.Sincos(tt.angle * math.Pi / 180)
+			width, height := inscribedRect(tt.w, tt.h, sin, cos)
+			if math.Abs(width-tt.width) > 1e-9 || math.Abs(height-tt.height) > 1e-9 {
+				t.Errorf("inscribedRect = %v x %v, want %v x %v", width, height, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestInscribedRectFits(t *testing.T) {
+	for _, size := range [][2]float64{{200, 100}, {100, 200}, {120, 100}, {100, 100}} {
+		for angle := 1.0; angle < 180; angle += 7 {
+			w, h := size[0], size[1]
+			sin, cos := math.Sincos(angle * math.Pi / 180)
+			width, height := inscribedRect(w, h, sin, cos)
+
+			// the corners of the rectangle, rotated back, lie inside the image
+			for _, corner := range []Point{{-width / 2, -height / 2}, {width / 2, -height / 2}, {width / 2, height / 2}, {-width / 2, height / 2}} {
+				x := corner.X*cos + corner.Y*sin
+				y := -corner.X*sin + corner.Y*cos
+				if math.Abs(x) > w/2+1e-9 || math.Abs(y) > h/2+1e-9 {
+					t.Errorf("%v x %v at %v: corner %v of %v x %v is outside", w, h, angle, corner, width, height)
+				}
+			}
+		}
+	}
+}
+
+func TestRotateCrop(t *testing.T) {
+	tests := []struct {
+		name          string
+		w, h          int
+		angle         float64
+		width, height int
+	}{
+		{"30", 200, 100, 30, 100, 57},
+		{"-30", 200, 100, -30, 100, 57},
+		{"45", 100, 100, 45, 70, 70},
+		{"90", 200, 100, 90, 100, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := Canvas(tt.w, tt.h, color.White).RotateAngle(tt.angle, RotateOptions{Mode: RotateCrop})
+			if img.Error != nil {
+				t.Fatal(img.Error)
+			}
+			if img.width != tt.width || img.height != tt.height {
+				t.Fatalf("size = %d x %d, want %d x %d", img.width, img.height, tt.width, tt.height)
+			}
+
+			// the crop is inside the rotated image, so no pixel is uncovered
+			for y := 0; y < img.height; y++ {
+				for x := 0; x < img.width; x++ {
+					if a := img.image.RGBAAt(x, y).A; a < 250 {
+						t.Fatalf("pixel (%d, %d) has alpha %d", x, y, a)
+					}
+				}
+			}
+		})
+	}
+}
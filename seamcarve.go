@@ -0,0 +1,302 @@
+package imgo
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// the energy added to the pixels of the protect and remove masks of SeamCarve, larger than any edge
+const seamMaskEnergy = 1000
+
+// SeamCarveOptions is the options of SeamCarve.
+// The masks can be anything Mask accepts, and are stretched to the size of the image.
+type SeamCarveOptions struct {
+	Protect interface{} // mask of the areas to keep undistorted, such as people or logos
+	Remove  interface{} // mask of the object to remove, such as a person or a logo
+}
+
+// SeamCarve resizes the image to width x height with seam carving, a content-aware resize that
+// removes or inserts the paths of pixels with the least edges, so that important content is not distorted.
+// The masks only steer the seams that are removed: inserted seams follow the edges of the image alone.
+// With a Remove mask, seams are removed until none of the masked pixels remain, across the narrower
+// side of the object, and the image is then resized to width x height.
+func (i *Image) SeamCarve(width, height int, options ...SeamCarveOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if width <= 0 || height <= 0 {
+		return i
+	}
+
+	var opt SeamCarveOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	c := newCarver(i.image)
+	for _, m := range []struct {
+		source interface{}
+		energy float64
+	}{{opt.Protect, seamMaskEnergy}, {opt.Remove, -seamMaskEnergy}} {
+		if m.source == nil {
+			continue
+		}
+		mask, err := loadMask(m.source)
+		if err != nil {
+			i.addError(err, true)
+			return i
+		}
+		coverage := coverageOf(mask, MaskAuto, i.image.Bounds())
+		for n := range c.bias {
+			c.bias[n] += m.energy * float64(coverage.Pix[n]) / 255
+			if m.energy < 0 && coverage.Pix[n] != 0 {
+				c.remove[n] = true
+			}
+		}
+	}
+
+	if c.removeObject() {
+		c.transpose()
+		c.resizeWidth(height)
+		c.transpose()
+		c.resizeWidth(width)
+	} else {
+		c.resizeWidth(width)
+		c.transpose()
+		c.resizeWidth(height)
+		c.transpose()
+	}
+
+	i.image = c.toRGBA()
+	i.width = c.w
+	i.height = c.h
+	return i
+}
+
+// carver is an image being seam carved.
+type carver struct {
+	w, h   int
+	pix    []uint8   // RGBA pixels, row by row
+	bias   []float64 // energy added to each pixel by the masks when removing seams
+	remove []bool    // pixels of the object to remove
+}
+
+// newCarver returns a carver of img.
+func newCarver(img *image.RGBA) *carver {
+	b := img.Bounds()
+	n := b.Dx() * b.Dy()
+	c := &carver{w: b.Dx(), h: b.Dy(), pix: make([]uint8, 4*n), bias: make([]float64, n), remove: make([]bool, n)}
+	for y := 0; y < c.h; y++ {
+		copy(c.pix[4*y*c.w:4*(y+1)*c.w], img.Pix[img.PixOffset(b.Min.X, b.Min.Y+y):])
+	}
+	return c
+}
+
+// removeObject removes seams until none of the pixels of the object to remove remain, and reports
+// whether it ended transposed. The seams are vertical if the object is taller than wide, so that
+// as few of them as possible are removed.
+func (c *carver) removeObject() bool {
+	bounds := image.Rectangle{}
+	for n, masked := range c.remove {
+		if masked {
+			bounds = bounds.Union(image.Rect(n%c.w, n/c.w, n%c.w+1, n/c.w+1))
+		}
+	}
+	if bounds.Empty() {
+		return false
+	}
+
+	transposed := bounds.Dx() > bounds.Dy()
+	if transposed {
+		c.transpose()
+	}
+	for c.w > 1 {
+		seam := c.findSeam(true)
+		removed := false
+		for y, x := range seam {
+			removed = removed || c.remove[y*c.w+x]
+		}
+		// the seams go through the object while it remains, so this is only a safeguard
+		if !removed {
+			break
+		}
+		c.removeSeam(seam)
+	}
+	return transposed
+}
+
+// toRGBA returns the carved image.
+func (c *carver) toRGBA() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, c.w, c.h))
+	copy(img.Pix, c.pix)
+	return img
+}
+
+// transpose swaps the rows and the columns, so that horizontal seams can be carved as vertical ones.
+func (c *carver) transpose() {
+	pix := make([]uint8, len(c.pix))
+	bias := make([]float64, len(c.bias))
+	remove := make([]bool, len(c.remove))
+	for y := 0; y < c.h; y++ {
+		for x := 0; x < c.w; x++ {
+			copy(pix[4*(x*c.h+y):4*(x*c.h+y)+4], c.pix[4*(y*c.w+x):4*(y*c.w+x)+4])
+			bias[x*c.h+y] = c.bias[y*c.w+x]
+			remove[x*c.h+y] = c.remove[y*c.w+x]
+		}
+	}
+	c.pix, c.bias, c.remove = pix, bias, remove
+	c.w, c.h = c.h, c.w
+}
+
+// resizeWidth removes or inserts vertical seams until the image is width pixels wide.
+func (c *carver) resizeWidth(width int) {
+	for c.w > width && c.w > 1 {
+		c.removeSeam(c.findSeam(true))
+	}
+
+	// seams are inserted in passes of at most half the width, so that they are spread out
+	for c.w < width {
+		n := width - c.w
+		if n > (c.w+1)/2 {
+			n = (c.w + 1) / 2
+		}
+		c.insertSeams(n)
+	}
+}
+
+// energy returns the energy of each pixel: its edges, plus the bias of the masks if biased.
+func (c *carver) energy(biased bool) []float64 {
+	lightness := make([]float64, c.w*c.h)
+	for n := range lightness {
+		p := c.pix[4*n:]
+		lightness[n] = (0.2126*float64(p[0]) + 0.7152*float64(p[1]) + 0.0722*float64(p[2]) + float64(p[3])) / 255
+	}
+
+	energy := sobel(lightness, c.w, c.h)
+	if biased {
+		for n := range energy {
+			energy[n] += c.bias[n]
+		}
+	}
+	return energy
+}
+
+// findSeam returns the column of each row of the vertical seam with the least energy,
+// with the bias of the masks if biased.
+func (c *carver) findSeam(biased bool) []int {
+	energy := c.energy(biased)
+
+	// the least energy of a seam from the top row to each pixel
+	cost := make([]float64, len(energy))
+	copy(cost[:c.w], energy[:c.w])
+	for y := 1; y < c.h; y++ {
+		for x := 0; x < c.w; x++ {
+			least := cost[(y-1)*c.w+x]
+			if x > 0 {
+				least = math.Min(least, cost[(y-1)*c.w+x-1])
+			}
+			if x < c.w-1 {
+				least = math.Min(least, cost[(y-1)*c.w+x+1])
+			}
+			cost[y*c.w+x] = energy[y*c.w+x] + least
+		}
+	}
+
+	// walk back from the cheapest pixel of the bottom row
+	seam := make([]int, c.h)
+	last := (c.h - 1) * c.w
+	for x := 1; x < c.w; x++ {
+		if cost[last+x] < cost[last+seam[c.h-1]] {
+			seam[c.h-1] = x
+		}
+	}
+	for y := c.h - 2; y >= 0; y-- {
+		x := seam[y+1]
+		best := x
+		for _, nx := range [2]int{x - 1, x + 1} {
+			if nx >= 0 && nx < c.w && cost[y*c.w+nx] < cost[y*c.w+best] {
+				best = nx
+			}
+		}
+		seam[y] = best
+	}
+
+	return seam
+}
+
+// removeSeam removes the pixel of each row at the column of the seam.
+func (c *carver) removeSeam(seam []int) {
+	w := c.w - 1
+	pix := make([]uint8, 4*w*c.h)
+	bias := make([]float64, w*c.h)
+	remove := make([]bool, w*c.h)
+	for y, x := range seam {
+		row, dst := y*c.w, y*w
+		copy(pix[4*dst:], c.pix[4*row:4*(row+x)])
+		copy(pix[4*(dst+x):], c.pix[4*(row+x+1):4*(row+c.w)])
+		copy(bias[dst:], c.bias[row:row+x])
+		copy(bias[dst+x:], c.bias[row+x+1:row+c.w])
+		copy(remove[dst:], c.remove[row:row+x])
+		copy(remove[dst+x:], c.remove[row+x+1:row+c.w])
+	}
+	c.pix, c.bias, c.remove, c.w = pix, bias, remove, w
+}
+
+// insertSeams widens the image by n pixels, duplicating the n seams with the least energy.
+// The seams are found by removing them from a copy, which keeps track of their original columns.
+func (c *carver) insertSeams(n int) {
+	work := &carver{w: c.w, h: c.h, pix: c.pix, bias: c.bias, remove: c.remove}
+
+	// the original column of each pixel of the copy
+	columns := make([][]int, c.h)
+	for y := range columns {
+		columns[y] = make([]int, c.w)
+		for x := range columns[y] {
+			columns[y][x] = x
+		}
+	}
+
+	inserted := make([][]int, c.h)
+	for k := 0; k < n; k++ {
+		seam := work.findSeam(false)
+		for y, x := range seam {
+			inserted[y] = append(inserted[y], columns[y][x])
+			columns[y] = append(columns[y][:x], columns[y][x+1:]...)
+		}
+		work.removeSeam(seam)
+	}
+
+	// each seam pixel is followed by the average of itself and its right neighbour
+	w := c.w + n
+	pix := make([]uint8, 4*w*c.h)
+	bias := make([]float64, w*c.h)
+	remove := make([]bool, w*c.h)
+	for y := 0; y < c.h; y++ {
+		sort.Ints(inserted[y])
+		dst := y * w
+		next := 0
+		for x := 0; x < c.w; x++ {
+			src := y*c.w + x
+			copy(pix[4*dst:4*dst+4], c.pix[4*src:4*src+4])
+			bias[dst] = c.bias[src]
+			remove[dst] = c.remove[src]
+			dst++
+			for next < len(inserted[y]) && inserted[y][next] == x {
+				right := src
+				if x < c.w-1 {
+					right = src + 1
+				}
+				for ch := 0; ch < 4; ch++ {
+					pix[4*dst+ch] = uint8((int(c.pix[4*src+ch]) + int(c.pix[4*right+ch]) + 1) / 2)
+				}
+				bias[dst] = c.bias[src]
+				remove[dst] = c.remove[src]
+				dst++
+				next++
+			}
+		}
+	}
+	c.pix, c.bias, c.remove, c.w = pix, bias, remove, w
+}
@@ -0,0 +1,167 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Shadow is a drop shadow, of a text or an image.
+type Shadow struct {
+	OffsetX int         // horizontal offset of the shadow, in pixels
+	OffsetY int         // vertical offset of the shadow, in pixels
+	Blur    float64     // standard deviation of the Gaussian blur of the shadow, 0 means a hard shadow
+	Color   color.Color // shadow color, nil is treated as black
+}
+
+// DropShadow draws a shadow behind the image, offset by (offsetX, offsetY) and blurred with
+// the standard deviation blur. The shadow follows the alpha channel of the image, so it has
+// the shape of rounded or masked images. The image grows so that the shadow is not clipped.
+// opacity is the opacity of the shadow, between 0 and 1.
+func (i *Image) DropShadow(offsetX, offsetY int, blur float64, c color.Color, opacity float64) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if opacity <= 0 {
+		return i
+	}
+
+	margin := int(math.Ceil(3 * math.Max(0, blur)))
+	i.growTo(i.image.Bounds().Inset(-margin).Add(image.Pt(offsetX, offsetY)))
+
+	return i.drawBehind(alphaOf(i.image), Shadow{
+		OffsetX: offsetX,
+		OffsetY: offsetY,
+		Blur:    blur,
+		Color:   withOpacity(colorOrBlack(c), opacity),
+	})
+}
+
+// OuterGlow draws a glow of given size around the image, following its alpha channel.
+// The image grows so that the glow is not clipped.
+// opacity is the opacity of the glow, between 0 and 1.
+func (i *Image) OuterGlow(size float64, c color.Color, opacity float64) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if size <= 0 || opacity <= 0 {
+		return i
+	}
+
+	// the glow is the shape grown by half the size, and blurred over the other half
+	spread, blur := size/2, size/6
+	margin := int(math.Ceil(spread+0.5)) + int(math.Ceil(3*blur))
+	i.growTo(i.image.Bounds().Inset(-margin))
+
+	return i.drawBehind(dilateAlpha(alphaOf(i.image), spread), Shadow{
+		Blur:  blur,
+		Color: withOpacity(colorOrBlack(c), opacity),
+	})
+}
+
+// InnerGlow draws a glow of given size inside the edges of the image, following its alpha channel.
+// opacity is the opacity of the glow, between 0 and 1.
+func (i *Image) InnerGlow(size float64, c color.Color, opacity float64) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if size <= 0 || opacity <= 0 {
+		return i
+	}
+
+	// the glow is the outside of the shape grown inwards, outside of the image counts as outside
+	spread, blur := size/2, size/6
+	shape := alphaOf(i.image)
+	bounds := shape.Bounds()
+	outside := image.NewAlpha(bounds.Inset(-int(math.Ceil(3 * blur))))
+	for y := outside.Rect.Min.Y; y < outside.Rect.Max.Y; y++ {
+		for x := outside.Rect.Min.X; x < outside.Rect.Max.X; x++ {
+			outside.Pix[outside.PixOffset(x, y)] = 0xff - alphaAt(shape, x, y)
+		}
+	}
+
+	layer, origin, err := blurredLayer(dilateAlpha(outside, spread), withOpacity(colorOrBlack(c), opacity), blur)
+	if err != nil {
+		i.addError(err)
+		return i
+	}
+
+	// only the part of the glow inside the shape is drawn
+	draw.DrawMask(i.image, bounds, layer, bounds.Min.Sub(origin), shape, bounds.Min, draw.Over)
+
+	return i
+}
+
+// drawBehind draws the shadow of shape behind the image.
+func (i *Image) drawBehind(shape *image.Alpha, shadow Shadow) *Image {
+	src := i.image
+	i.image = image.NewRGBA(src.Bounds())
+	i.drawShadow(shape, shadow)
+	draw.Draw(i.image, src.Bounds(), src, src.Bounds().Min, draw.Over)
+	return i
+}
+
+// drawShadow draws the shadow of shape, offset and blurred.
+func (i *Image) drawShadow(shape *image.Alpha, shadow Shadow) {
+	layer, origin, err := blurredLayer(shape, colorOrBlack(shadow.Color), shadow.Blur)
+	if err != nil {
+		i.addError(err)
+		return
+	}
+
+	bounds := layer.Bounds().Add(origin)
+	draw.Draw(i.image, bounds.Add(image.Pt(shadow.OffsetX, shadow.OffsetY)), layer, image.Point{}, draw.Over)
+}
+
+// blurredLayer returns shape painted with c and blurred with GaussianBlur, on a layer with room for
+// the blur to spread beyond the shape. The layer starts at (0, 0), and origin is its position on the image.
+func blurredLayer(shape *image.Alpha, c color.Color, blur float64) (layer *image.RGBA, origin image.Point, err error) {
+	margin := int(math.Ceil(3 * blur))
+	bounds := shape.Bounds().Inset(-margin)
+	origin = bounds.Min
+
+	layer = image.NewRGBA(bounds.Sub(origin))
+	draw.DrawMask(layer, shape.Bounds().Sub(origin), image.NewUniform(c), image.Point{}, shape, shape.Bounds().Min, draw.Over)
+
+	if blur > 0 {
+		blurred := (&Image{image: layer, width: layer.Rect.Dx(), height: layer.Rect.Dy()}).GaussianBlur(2*margin+1, blur)
+		if blurred.Error != nil {
+			return nil, origin, blurred.Error
+		}
+		layer = blurred.image
+	}
+
+	return layer, origin, nil
+}
+
+// growTo grows the image with transparent pixels so that it contains rect, in image coordinates.
+// The image keeps its origin at (0, 0), so its content moves when it grows up or left.
+func (i *Image) growTo(rect image.Rectangle) {
+	bounds := i.image.Bounds()
+	union := bounds.Union(rect)
+	if union == bounds {
+		return
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, union.Dx(), union.Dy()))
+	draw.Draw(dst, bounds.Sub(union.Min), i.image, bounds.Min, draw.Src)
+	i.image = dst
+	i.width = union.Dx()
+	i.height = union.Dy()
+}
+
+// alphaOf returns the alpha channel of img.
+func alphaOf(img *image.RGBA) *image.Alpha {
+	b := img.Bounds()
+	mask := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			mask.Pix[mask.PixOffset(x, y)] = img.Pix[img.PixOffset(x, y)+3]
+		}
+	}
+	return mask
+}
@@ -3,6 +3,7 @@ package imgo
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
 // Pixel draws a pixel at given (x, y) coordinate with given color.
@@ -16,8 +17,9 @@ func (i *Image) Pixel(x, y int, c color.Color) *Image {
 	return i
 }
 
-// Line draws a line from (x1, y1) to (x2, y2) with given color.
-// TODO: width is not working yet.
+// Line draws an anti-aliased line from (x1, y1) to (x2, y2) with given color.
+// width is the line width in pixels, default is 1. The line has square caps, so that the
+// pixels at both ends are fully covered.
 func (i *Image) Line(x1, y1, x2, y2 int, c color.Color, width ...int) *Image {
 	if i.Error != nil {
 		return i
@@ -31,84 +33,71 @@ func (i *Image) Line(x1, y1, x2, y2 int, c color.Color, width ...int) *Image {
 		return i
 	}
 
-	bg := i.image
+	// the line goes through the centers of the pixels
+	points := []Point{Pt(float64(x1)+0.5, float64(y1)+0.5), Pt(float64(x2)+0.5, float64(y2)+0.5)}
 
-	dx := x2 - x1
-	dy := y2 - y1
-	if dx == 0 && dy == 0 { // Line is a point.
-		bg.Set(x1, y1, c)
-		i.image = bg
+	return i.Polyline(points, c, StrokeOptions{Width: float64(w), Cap: CapSquare})
+}
+
+// Polyline draws the lines connecting the points in order with given color.
+// The line is 1 pixel wide with butt caps and miter joins, unless options are given.
+func (i *Image) Polyline(points []Point, c color.Color, options ...StrokeOptions) *Image {
+	if i.Error != nil {
 		return i
-	} else if dx == 0 { // vertical line
-		if y1 > y2 {
-			y1, y2 = y2, y1
-		}
-		for y := y1; y <= y2; y++ {
-			bg.Set(x1, y, c)
-		}
-	} else if dy == 0 { // horizontal line
-		if x1 > x2 {
-			x1, x2 = x2, x1
-		}
-		for x := x1; x <= x2; x++ {
-			bg.Set(x, y1, c)
-		}
-	} else { // diagonal line
-		k := float64(dy) / float64(dx)
-		if x1 > x2 {
-			x1, x2 = x2, x1
-			y1, y2 = y2, y1
-		}
-		if -1 < k && k < 1 {
-			for x := x1; x <= x2; x++ {
-				y := int(float64(y1) + float64(x-x1)*k)
-				bg.Set(x, y, c)
-			}
-		} else {
-			ySmaller, yBigger := y1, y2
-			if y1 > y2 {
-				ySmaller, yBigger = y2, y1
-			}
-			for y := ySmaller; y <= yBigger; y++ {
-				x := int(float64(x1) + float64(y-y1)/k)
-				bg.Set(x, y, c)
-			}
-		}
 	}
 
+	var opt StrokeOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	polygons := strokePolyline(points, false, opt)
+	i.fillPolygons(polygons, NonZero, image.NewUniform(c), !opt.Aliased)
+
 	return i
 }
 
+// ShapeStyle is the style of Circle, Rectangle, RoundedRectangle and Ellipse.
+type ShapeStyle struct {
+	Fill        image.Image // image or gradient the shape is filled with, in image coordinates, overrides the color argument
+	Stroke      color.Color // outline color, nil means no outline
+	StrokeWidth float64     // outline width in pixels, centered on the edge of the shape, 0 is treated as 1
+	Aliased     bool        // disable anti-aliasing
+	Opacity     float64     // opacity of the shape between 0 and 1, 0 is treated as 1
+}
+
+// CornerRadii is the radii of the four corners of a rectangle.
+type CornerRadii struct {
+	TopLeft     float64
+	TopRight    float64
+	BottomRight float64
+	BottomLeft  float64
+}
+
+// UniformRadii returns the corner radii with all four corners of radius r.
+func UniformRadii(r float64) CornerRadii {
+	return CornerRadii{TopLeft: r, TopRight: r, BottomRight: r, BottomLeft: r}
+}
+
 // Circle draws a circle at given center coordinate (x, y) with given radius and color.
-func (i *Image) Circle(x, y, radius int, c color.Color) *Image {
+// Like Line, the center is the center of the pixel (x, y).
+// c is the fill color, nil means no fill. The parts outside the image are clipped.
+func (i *Image) Circle(x, y, radius int, c color.Color, style ...ShapeStyle) *Image {
 	if i.Error != nil {
 		return i
 	}
 
-	if radius <= 0 || radius >= i.width || radius >= i.height {
+	if radius <= 0 {
 		return i
 	}
 
-	x1 := x - radius
-	y1 := y - radius
-	x2 := x + radius
-	y2 := y + radius
-
-	bg := i.image
-
-	for x3 := x1; x3 < x2; x3++ {
-		for y3 := y1; y3 < y2; y3++ {
-			if (x3-x)*(x3-x)+(y3-y)*(y3-y) <= radius*radius {
-				bg.Set(x3, y3, c)
-			}
-		}
-	}
-
-	return i
+	outline := circlePolygon(Pt(float64(x)+0.5, float64(y)+0.5), float64(radius))
+	return i.drawShape(outline, JoinRound, c, style)
 }
 
 // Rectangle draws a rectangle at given coordinate (x, y) with given width and height and color.
-func (i *Image) Rectangle(x, y, width, height int, c color.Color) *Image {
+// c is the fill color, nil means no fill. The parts outside the image are clipped.
+func (i *Image) Rectangle(x, y, width, height int, c color.Color, style ...ShapeStyle) *Image {
 	if i.Error != nil {
 		return i
 	}
@@ -117,26 +106,32 @@ func (i *Image) Rectangle(x, y, width, height int, c color.Color) *Image {
 		return i
 	}
 
-	x1 := x
-	y1 := y
-	x2 := x + width
-	y2 := y + height
+	x1, y1 := float64(x), float64(y)
+	x2, y2 := float64(x+width), float64(y+height)
+	outline := []Point{Pt(x1, y1), Pt(x2, y1), Pt(x2, y2), Pt(x1, y2)}
+	return i.drawShape(outline, JoinMiter, c, style)
+}
 
-	bg := i.image
+// RoundedRectangle draws a rectangle with rounded corners at given coordinate (x, y) with given
+// width, height, corner radii and color. c is the fill color, nil means no fill.
+// Radii that don't fit in the rectangle are scaled down proportionally.
+func (i *Image) RoundedRectangle(x, y, width, height int, radii CornerRadii, c color.Color, style ...ShapeStyle) *Image {
+	if i.Error != nil {
+		return i
+	}
 
-	for x3 := x1; x3 < x2; x3++ {
-		for y3 := y1; y3 < y2; y3++ {
-			if image.Pt(x3, y3).In(i.image.Bounds()) {
-				bg.Set(x3, y3, c)
-			}
-		}
+	if width <= 0 || height <= 0 {
+		return i
 	}
 
-	return i
+	outline := roundedRectPolygon(Pt(float64(x), float64(y)), Pt(float64(x+width), float64(y+height)), radii, radii)
+	return i.drawShape(outline, JoinRound, c, style)
 }
 
 // Ellipse draws an ellipse at given center coordinate (x, y) with given width and height and color.
-func (i *Image) Ellipse(x, y, width, height int, c color.Color) *Image {
+// Like Line, the center is the center of the pixel (x, y).
+// c is the fill color, nil means no fill. The parts outside the image are clipped.
+func (i *Image) Ellipse(x, y, width, height int, c color.Color, style ...ShapeStyle) *Image {
 	if i.Error != nil {
 		return i
 	}
@@ -145,22 +140,118 @@ func (i *Image) Ellipse(x, y, width, height int, c color.Color) *Image {
 		return i
 	}
 
-	a := float64(width) / 2
-	b := float64(height) / 2
-	x1 := x - int(a)
-	y1 := y - int(b)
-	x2 := x + int(a)
-	y2 := y + int(b)
+	outline := ellipsePolygon(Pt(float64(x)+0.5, float64(y)+0.5), float64(width)/2, float64(height)/2)
+	return i.drawShape(outline, JoinRound, c, style)
+}
 
-	bg := i.image
+// drawShape fills and strokes the closed outline of a shape with the style.
+func (i *Image) drawShape(outline []Point, join LineJoin, c color.Color, style []ShapeStyle) *Image {
+	var s ShapeStyle
+	if len(style) > 0 {
+		s = style[0]
+	}
 
-	for x3 := x1; x3 <= x2; x3++ {
-		for y3 := y1; y3 <= y2; y3++ {
-			if (float64(x3)-float64(x))*(float64(x3)-float64(x))/a/a+(float64(y3)-float64(y))*(float64(y3)-float64(y))/b/b <= 1.0 {
-				bg.Set(x3, y3, c)
-			}
+	var fill image.Image
+	if s.Fill != nil {
+		fill = s.Fill
+		if s.Opacity > 0 && s.Opacity < 1 {
+			fill = &opacitySource{src: fill, opacity: s.Opacity}
 		}
+	} else if c != nil {
+		fill = image.NewUniform(withOpacity(c, s.Opacity))
+	}
+
+	if fill != nil {
+		i.fillPolygons([][]Point{outline}, NonZero, fill, !s.Aliased)
+	}
+
+	if s.Stroke != nil {
+		polygons := strokePolyline(outline, true, StrokeOptions{Width: s.StrokeWidth, Join: join})
+		i.fillPolygons(polygons, NonZero, image.NewUniform(withOpacity(s.Stroke, s.Opacity)), !s.Aliased)
 	}
 
 	return i
 }
+
+// roundedRectPolygon returns the outline of the rectangle from min to max with elliptical corners
+// of horizontal radii rx and vertical radii ry, fitted with fitRadii.
+func roundedRectPolygon(min, max Point, rx, ry CornerRadii) []Point {
+	rx, ry = fitRadii(max.X-min.X, max.Y-min.Y, rx, ry)
+	radii := [4][2]float64{
+		{rx.TopLeft, ry.TopLeft},
+		{rx.TopRight, ry.TopRight},
+		{rx.BottomRight, ry.BottomRight},
+		{rx.BottomLeft, ry.BottomLeft},
+	}
+	x1, y1, x2, y2 := min.X, min.Y, max.X, max.Y
+
+	// the corners in clockwise order, with the center of their arc and start angle
+	corners := [4]struct {
+		corner Point
+		cx, cy float64
+		angle  float64
+	}{
+		{Pt(x1, y1), 1, 1, math.Pi},
+		{Pt(x2, y1), -1, 1, 3 * math.Pi / 2},
+		{Pt(x2, y2), -1, -1, 0},
+		{Pt(x1, y2), 1, -1, math.Pi / 2},
+	}
+
+	var polygon []Point
+	for n, c := range corners {
+		a, b := radii[n][0], radii[n][1]
+		if a <= 0 || b <= 0 {
+			polygon = append(polygon, c.corner)
+			continue
+		}
+		center := Pt(c.corner.X+c.cx*a, c.corner.Y+c.cy*b)
+		segments := arcSegments(math.Max(a, b), math.Pi/2)
+		for k := 0; k <= segments; k++ {
+			angle := c.angle + math.Pi/2*float64(k)/float64(segments)
+			polygon = append(polygon, Pt(center.X+a*math.Cos(angle), center.Y+b*math.Sin(angle)))
+		}
+	}
+
+	return polygon
+}
+
+// fitRadii returns the corner radii of a width x height rectangle, without negative radii.
+// Like CSS border-radius, all radii are scaled down by the same factor when the radii of two
+// adjacent corners don't fit on the side between them.
+func fitRadii(width, height float64, rx, ry CornerRadii) (CornerRadii, CornerRadii) {
+	rx = CornerRadii{math.Max(0, rx.TopLeft), math.Max(0, rx.TopRight), math.Max(0, rx.BottomRight), math.Max(0, rx.BottomLeft)}
+	ry = CornerRadii{math.Max(0, ry.TopLeft), math.Max(0, ry.TopRight), math.Max(0, ry.BottomRight), math.Max(0, ry.BottomLeft)}
+
+	scale := 1.0
+	for _, side := range [][2]float64{
+		{rx.TopLeft + rx.TopRight, width},
+		{rx.BottomLeft + rx.BottomRight, width},
+		{ry.TopLeft + ry.BottomLeft, height},
+		{ry.TopRight + ry.BottomRight, height},
+	} {
+		if side[0] > side[1] {
+			scale = math.Min(scale, side[1]/side[0])
+		}
+	}
+
+	return rx.scale(scale), ry.scale(scale)
+}
+
+// scale returns the radii multiplied by factor.
+func (r CornerRadii) scale(factor float64) CornerRadii {
+	return CornerRadii{r.TopLeft * factor, r.TopRight * factor, r.BottomRight * factor, r.BottomLeft * factor}
+}
+
+// withOpacity returns c with its alpha multiplied by opacity. An opacity of 0 is treated as 1.
+func withOpacity(c color.Color, opacity float64) color.Color {
+	if opacity <= 0 || opacity >= 1 {
+		return c
+	}
+	r, g, b, a := c.RGBA()
+	return color.RGBA64{
+		R: uint16(float64(r) * opacity),
+		G: uint16(float64(g) * opacity),
+		B: uint16(float64(b) * opacity),
+		A: uint16(float64(a) * opacity),
+	}
+}
@@ -0,0 +1,247 @@
+package imgo
+
+import (
+	"github.com/nfnt/resize"
+	"image"
+	"math"
+)
+
+// the weights of the features of SmartCrop, after smartcrop.js
+const (
+	smartCropSize             = 256 // the size of the longest side of the image the features are computed on
+	smartCropStep             = 4   // the step between candidate windows, in pixels of the scaled image
+	smartCropDetailWeight     = 0.2
+	smartCropSkinWeight       = 1.8
+	smartCropSkinBias         = 0.01
+	smartCropSaturationWeight = 0.1
+	smartCropSaturationBias   = 0.2
+	smartCropEntropyWeight    = 0.1
+	smartCropEdgeRadius       = 0.4
+	smartCropEdgeWeight       = -20.0
+)
+
+// smartCropFeatures is the interest of each pixel of the scaled image.
+type smartCropFeatures struct {
+	width, height int
+	detail        []float64 // edge density, from the gradient of the lightness
+	skin          []float64 // closeness to skin tones
+	saturation    []float64 // color saturation
+	entropy       []float64 // entropy of the lightness around the pixel
+}
+
+// SmartCrop crops the image to the most interesting region with the aspect ratio of width:height,
+// and resizes it to width x height, for automatic thumbnails. The region is chosen with edges,
+// skin tones, saturation and entropy, like smartcrop.js.
+func (i *Image) SmartCrop(width, height int) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	rect := i.SmartCropRect(width, height)
+	if rect.Empty() {
+		return i
+	}
+
+	return i.Crop(rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy()).Resize(width, height)
+}
+
+// SmartCropRect returns the region SmartCrop would crop the image to, relative to the top-left
+// corner of the image, without changing the image.
+func (i Image) SmartCropRect(width, height int) image.Rectangle {
+	if i.Error != nil || width <= 0 || height <= 0 || i.width == 0 || i.height == 0 {
+		return image.Rectangle{}
+	}
+
+	// the largest window with the aspect ratio, in the original image
+	cw, ch := i.width, int(math.Round(float64(i.width)*float64(height)/float64(width)))
+	if ch > i.height {
+		cw, ch = int(math.Round(float64(i.height)*float64(width)/float64(height))), i.height
+	}
+	if cw < 1 {
+		cw = 1
+	}
+	if ch < 1 {
+		ch = 1
+	}
+	if cw == i.width && ch == i.height {
+		return image.Rect(0, 0, cw, ch)
+	}
+
+	// the features are computed on a smaller copy
+	scale := math.Min(1, smartCropSize/math.Max(float64(i.width), float64(i.height)))
+	var small *image.RGBA
+	if scale < 1 {
+		small = Image2RGBA(resize.Resize(uint(math.Max(1, math.Round(float64(i.width)*scale))), 0, i.image, resize.Bilinear))
+	} else {
+		small = i.image
+	}
+	features := smartCropFeaturesOf(small)
+
+	// slide the window along the free axis, and keep the one with the best score
+	sw, sh := float64(cw)*scale, float64(ch)*scale
+	best, bestScore := image.Point{}, math.Inf(-1)
+	for y := 0.0; y+sh <= float64(features.height)+0.5; y += smartCropStep {
+		for x := 0.0; x+sw <= float64(features.width)+0.5; x += smartCropStep {
+			if s := features.score(x, y, sw, sh); s > bestScore {
+				best, bestScore = image.Pt(int(math.Round(x/scale)), int(math.Round(y/scale))), s
+			}
+		}
+	}
+
+	// keep the window inside the image after rounding
+	if best.X+cw > i.width {
+		best.X = i.width - cw
+	}
+	if best.Y+ch > i.height {
+		best.Y = i.height - ch
+	}
+
+	return image.Rect(best.X, best.Y, best.X+cw, best.Y+ch)
+}
+
+// smartCropFeaturesOf computes the features of each pixel of img.
+func smartCropFeaturesOf(img *image.RGBA) *smartCropFeatures {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	f := &smartCropFeatures{
+		width:      w,
+		height:     h,
+		detail:     make([]float64, w*h),
+		skin:       make([]float64, w*h),
+		saturation: make([]float64, w*h),
+		entropy:    make([]float64, w*h),
+	}
+
+	// the lightness, between 0 and 1
+	lightness := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			p := img.Pix[img.PixOffset(b.Min.X+x, b.Min.Y+y):]
+			lightness[y*w+x] = (0.2126*float64(p[0]) + 0.7152*float64(p[1]) + 0.0722*float64(p[2])) / 255
+		}
+	}
+
+	edges := sobel(lightness, w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			n := y*w + x
+			p := img.Pix[img.PixOffset(b.Min.X+x, b.Min.Y+y):]
+			r, g, bl := float64(p[0])/255, float64(p[1])/255, float64(p[2])/255
+			l := lightness[n]
+			f.detail[n] = math.Min(1, edges[n])
+
+			// distance of the normalized color to a skin tone
+			mag := math.Sqrt(r*r + g*g + bl*bl)
+			skin := 0.0
+			if mag > 0 {
+				dr, dg, db := r/mag-0.78, g/mag-0.57, bl/mag-0.44
+				skin = 1 - math.Sqrt(dr*dr+dg*dg+db*db)
+			}
+			if skin > 0.8 && l >= 0.2 && l <= 1 {
+				f.skin[n] = (skin - 0.8) / 0.2
+			}
+
+			hi, lo := math.Max(r, math.Max(g, bl)), math.Min(r, math.Min(g, bl))
+			saturation := 0.0
+			if hi > 0 {
+				saturation = (hi - lo) / hi
+			}
+			if saturation > 0.4 && l >= 0.05 && l <= 0.9 {
+				f.saturation[n] = (saturation - 0.4) / 0.6
+			}
+		}
+	}
+
+	// the entropy of the histogram of the lightness in blocks of 8 x 8 pixels, between 0 and 1
+	const block, bins = 8, 16
+	for by := 0; by < h; by += block {
+		for bx := 0; bx < w; bx += block {
+			var histogram [bins]int
+			count := 0
+			for y := by; y < by+block && y < h; y++ {
+				for x := bx; x < bx+block && x < w; x++ {
+					histogram[int(math.Min(bins-1, lightness[y*w+x]*bins))]++
+					count++
+				}
+			}
+			entropy := 0.0
+			for _, c := range histogram {
+				if c > 0 {
+					p := float64(c) / float64(count)
+					entropy -= p * math.Log2(p)
+				}
+			}
+			entropy /= math.Log2(bins)
+			for y := by; y < by+block && y < h; y++ {
+				for x := bx; x < bx+block && x < w; x++ {
+					f.entropy[y*w+x] = entropy
+				}
+			}
+		}
+	}
+
+	return f
+}
+
+// score returns the interest of the window at (x, y) with given width and height.
+// Pixels are weighted by their importance in the window, which favors the center and the
+// rule-of-thirds lines, and penalizes the edges, so that interesting parts are not cut.
+func (f *smartCropFeatures) score(x, y, width, height float64) float64 {
+	var detail, skin, saturation, entropy float64
+	x0, y0 := int(x), int(y)
+	x1, y1 := int(math.Min(float64(f.width), math.Ceil(x+width))), int(math.Min(float64(f.height), math.Ceil(y+height)))
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			importance := smartCropImportance((float64(px)+0.5-x)/width, (float64(py)+0.5-y)/height)
+			n := py*f.width + px
+			d := f.detail[n]
+			detail += d * importance
+			skin += f.skin[n] * (d + smartCropSkinBias) * importance
+			saturation += f.saturation[n] * (d + smartCropSaturationBias) * importance
+			entropy += f.entropy[n] * importance
+		}
+	}
+
+	total := detail*smartCropDetailWeight + skin*smartCropSkinWeight + saturation*smartCropSaturationWeight + entropy*smartCropEntropyWeight
+	return total / (width * height)
+}
+
+// smartCropImportance returns the importance of the point (x, y) of a window, with coordinates between 0 and 1.
+func smartCropImportance(x, y float64) float64 {
+	if x < 0 || x > 1 || y < 0 || y > 1 {
+		return 0
+	}
+
+	// distance to the center, 0 at the center and 1 on the edges
+	px, py := math.Abs(0.5-x)*2, math.Abs(0.5-y)*2
+	dx := math.Max(px-1+smartCropEdgeRadius, 0)
+	dy := math.Max(py-1+smartCropEdgeRadius, 0)
+	d := (dx*dx + dy*dy) * smartCropEdgeWeight
+
+	s := 1.41 - math.Sqrt(px*px+py*py)
+	s += math.Max(0, s+d+0.5) * 1.2 * (smartCropThirds(px) + smartCropThirds(py))
+	return s + d
+}
+
+// smartCropThirds is high on the rule-of-thirds lines of a window, for a distance x to its center between 0 and 1.
+func smartCropThirds(x float64) float64 {
+	x = (math.Mod(x-1.0/3+1, 2)*0.5 - 0.5) * 16
+	return math.Max(1-x*x, 0)
+}
+
+// sobel returns the gradient magnitude of the w x h values, with the edges extended.
+func sobel(values []float64, w, h int) []float64 {
+	at := func(x, y int) float64 {
+		return values[extendCoordinate(y, h, false)*w+extendCoordinate(x, w, false)]
+	}
+
+	magnitude := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			gx := at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy := at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+			magnitude[y*w+x] = math.Hypot(gx, gy)
+		}
+	}
+	return magnitude
+}
@@ -0,0 +1,281 @@
+package imgo
+
+import (
+	"math"
+)
+
+// StrokeOptions is the options of stroked lines.
+type StrokeOptions struct {
+	Width      float64   // line width in pixels, 0 is treated as 1
+	Cap        LineCap   // shape of the ends of open lines and dashes
+	Join       LineJoin  // shape of the corners between two segments
+	MiterLimit float64   // maximum ratio of miter length to line width before a miter is beveled, 0 is treated as 4
+	Dash       []float64 // lengths of alternating dashes and gaps in pixels, nil means a solid line
+	DashOffset float64   // distance into the dash pattern to start at
+	Aliased    bool      // disable anti-aliasing
+}
+
+// curveTolerance is the maximum distance in pixels between a curve and the polyline approximating it.
+const curveTolerance = 0.1
+
+// strokePolyline returns the polygons covering the stroke of a polyline. The polygons have the
+// same orientation, so that they can be combined with the non-zero fill rule.
+func strokePolyline(points []Point, closed bool, options StrokeOptions) [][]Point {
+	points = dedupPoints(points, closed)
+	if len(points) == 0 {
+		return nil
+	}
+
+	if len(options.Dash) > 0 {
+		var polygons [][]Point
+		for _, dash := range dashPolyline(points, closed, options.Dash, options.DashOffset) {
+			polygons = append(polygons, strokePolyline(dash, false, StrokeOptions{
+				Width:      options.Width,
+				Cap:        options.Cap,
+				Join:       options.Join,
+				MiterLimit: options.MiterLimit,
+			})...)
+		}
+		return polygons
+	}
+
+	hw := options.Width / 2
+	if options.Width <= 0 {
+		hw = 0.5
+	}
+	miterLimit := options.MiterLimit
+	if miterLimit <= 0 {
+		miterLimit = 4
+	}
+
+	var polygons [][]Point
+	add := func(polygon ...Point) {
+		polygons = append(polygons, orient(polygon))
+	}
+
+	// a single point only has caps, drawn as if the line was horizontal
+	if len(points) == 1 {
+		p := points[0]
+		switch options.Cap {
+		case CapRound:
+			add(circlePolygon(p, hw)...)
+		case CapSquare:
+			add(Pt(p.X-hw, p.Y-hw), Pt(p.X+hw, p.Y-hw), Pt(p.X+hw, p.Y+hw), Pt(p.X-hw, p.Y+hw))
+		}
+		return polygons
+	}
+
+	segments := len(points) - 1
+	if closed {
+		segments = len(points)
+	}
+
+	// the body of each segment
+	for n := 0; n < segments; n++ {
+		p, q := points[n], points[(n+1)%len(points)]
+		normal := unitNormal(p, q).Mul(hw)
+		add(p.Add(normal), q.Add(normal), q.Sub(normal), p.Sub(normal))
+	}
+
+	// the joins between two segments
+	for n := 0; n < len(points); n++ {
+		if !closed && (n == 0 || n == len(points)-1) {
+			continue
+		}
+		prev := points[(n+len(points)-1)%len(points)]
+		p := points[n]
+		next := points[(n+1)%len(points)]
+		for _, polygon := range joinPolygons(prev, p, next, hw, options.Join, miterLimit) {
+			add(polygon...)
+		}
+	}
+
+	// the caps at both ends of an open line
+	if !closed {
+		for _, end := range [][2]Point{{points[0], points[1]}, {points[len(points)-1], points[len(points)-2]}} {
+			p, from := end[0], end[1]
+			switch options.Cap {
+			case CapRound:
+				add(circlePolygon(p, hw)...)
+			case CapSquare:
+				direction := unitVector(from, p).Mul(hw)
+				normal := unitNormal(from, p).Mul(hw)
+				add(p.Add(normal), p.Add(normal).Add(direction), p.Sub(normal).Add(direction), p.Sub(normal))
+			}
+		}
+	}
+
+	return polygons
+}
+
+// joinPolygons returns the polygons of the join at p between the segments prev-p and p-next.
+func joinPolygons(prev, p, next Point, hw float64, join LineJoin, miterLimit float64) [][]Point {
+	if join == JoinRound {
+		return [][]Point{circlePolygon(p, hw)}
+	}
+
+	// the outer side of the corner is opposite to the turning direction
+	d0, d1 := unitVector(prev, p), unitVector(p, next)
+	turn := d0.X*d1.Y - d0.Y*d1.X
+	if math.Abs(turn) < 1e-9 && d0.X*d1.X+d0.Y*d1.Y > 0 {
+		return nil // straight line, no corner to fill
+	}
+	n0, n1 := unitNormal(prev, p).Mul(hw), unitNormal(p, next).Mul(hw)
+	if turn > 0 {
+		n0, n1 = n0.Mul(-1), n1.Mul(-1)
+	}
+	a, b := p.Add(n0), p.Add(n1)
+
+	if join == JoinMiter {
+		// the miter tip is where the outer edges of both segments meet
+		cosHalf := math.Sqrt(math.Max(0, (1+d0.X*d1.X+d0.Y*d1.Y)/2))
+		if cosHalf > 1e-9 && 1/cosHalf <= miterLimit {
+			bisector := n0.Add(n1)
+			length := math.Hypot(bisector.X, bisector.Y)
+			if length > 1e-9 {
+				tip := p.Add(bisector.Mul(hw / cosHalf / length))
+				return [][]Point{{p, a, tip, b}}
+			}
+		}
+	}
+
+	return [][]Point{{p, a, b}}
+}
+
+// dashPolyline splits a polyline into the dashes of the dash pattern.
+func dashPolyline(points []Point, closed bool, pattern []float64, offset float64) [][]Point {
+	var total float64
+	for _, length := range pattern {
+		if length < 0 {
+			return [][]Point{points}
+		}
+		total += length
+	}
+	if total <= 0 {
+		return [][]Point{points}
+	}
+	if closed {
+		points = append(points, points[0])
+	}
+
+	// find where in the pattern the line starts
+	index := 0
+	remaining := pattern[0]
+	offset = math.Mod(offset, total)
+	if offset < 0 {
+		offset += total
+	}
+	for offset > 0 {
+		if offset < remaining {
+			remaining -= offset
+			break
+		}
+		offset -= remaining
+		index = (index + 1) % len(pattern)
+		remaining = pattern[index]
+	}
+
+	var dashes [][]Point
+	var current []Point
+	if index%2 == 0 {
+		current = []Point{points[0]}
+	}
+	for n := 0; n+1 < len(points); n++ {
+		p, q := points[n], points[n+1]
+		length := math.Hypot(q.X-p.X, q.Y-p.Y)
+		pos := 0.0
+		for length-pos > remaining {
+			pos += remaining
+			cut := p.Add(q.Sub(p).Mul(pos / length))
+			if index%2 == 0 {
+				dashes = append(dashes, append(current, cut))
+				current = nil
+			} else {
+				current = []Point{cut}
+			}
+			index = (index + 1) % len(pattern)
+			remaining = pattern[index]
+		}
+		remaining -= length - pos
+		if index%2 == 0 {
+			current = append(current, q)
+		}
+	}
+	if index%2 == 0 && len(current) > 1 {
+		dashes = append(dashes, current)
+	}
+
+	return dashes
+}
+
+// dedupPoints removes consecutive duplicated points, and the last point of a closed polyline
+// if it is the same as the first one.
+func dedupPoints(points []Point, closed bool) []Point {
+	var result []Point
+	for _, p := range points {
+		if len(result) == 0 || p != result[len(result)-1] {
+			result = append(result, p)
+		}
+	}
+	if closed && len(result) > 1 && result[0] == result[len(result)-1] {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+// circlePolygon returns a polygon approximating the circle of given center and radius.
+func circlePolygon(center Point, radius float64) []Point {
+	return ellipsePolygon(center, radius, radius)
+}
+
+// ellipsePolygon returns a polygon approximating the ellipse of given center and radii.
+func ellipsePolygon(center Point, rx, ry float64) []Point {
+	n := arcSegments(math.Max(rx, ry), 2*math.Pi)
+	polygon := make([]Point, n)
+	for k := range polygon {
+		angle := 2 * math.Pi * float64(k) / float64(n)
+		polygon[k] = Pt(center.X+rx*math.Cos(angle), center.Y+ry*math.Sin(angle))
+	}
+	return polygon
+}
+
+// arcSegments returns the number of segments needed to approximate an arc of given radius and
+// angle within curveTolerance.
+func arcSegments(radius, angle float64) int {
+	if radius <= curveTolerance {
+		return int(math.Max(3, math.Ceil(math.Abs(angle)/(math.Pi/2))))
+	}
+	step := 2 * math.Acos(1-curveTolerance/radius)
+	return int(math.Max(4, math.Ceil(math.Abs(angle)/step)))
+}
+
+// orient returns the polygon with its points in clockwise order in image coordinates.
+func orient(polygon []Point) []Point {
+	var area float64
+	for n := range polygon {
+		p, q := polygon[n], polygon[(n+1)%len(polygon)]
+		area += p.X*q.Y - q.X*p.Y
+	}
+	if area < 0 {
+		for a, b := 0, len(polygon)-1; a < b; a, b = a+1, b-1 {
+			polygon[a], polygon[b] = polygon[b], polygon[a]
+		}
+	}
+	return polygon
+}
+
+// unitVector returns the unit vector from p to q.
+func unitVector(p, q Point) Point {
+	d := q.Sub(p)
+	length := math.Hypot(d.X, d.Y)
+	if length == 0 {
+		return Pt(1, 0)
+	}
+	return d.Mul(1 / length)
+}
+
+// unitNormal returns the unit vector perpendicular to the direction from p to q.
+func unitNormal(p, q Point) Point {
+	d := unitVector(p, q)
+	return Pt(-d.Y, d.X)
+}
@@ -0,0 +1,49 @@
+package imgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDashPolylinePhase(t *testing.T) {
+	line := []Point{Pt(0, 0), Pt(10, 0)}
+
+	tests := []struct {
+		name    string
+		pattern []float64
+		offset  float64
+		want    [][2]float64 // start and end x of each dash
+	}{
+		{"no offset", []float64{2, 2}, 0, [][2]float64{{0, 2}, {4, 6}, {8, 10}}},
+		{"offset inside a dash", []float64{2, 2}, 1, [][2]float64{{0, 1}, {3, 5}, {7, 9}}},
+		{"offset inside a gap", []float64{2, 2}, 3, [][2]float64{{1, 3}, {5, 7}, {9, 10}}},
+		{"negative offset", []float64{2, 2}, -1, [][2]float64{{1, 3}, {5, 7}, {9, 10}}},
+		{"offset of a whole pattern", []float64{2, 2}, 8, [][2]float64{{0, 2}, {4, 6}, {8, 10}}},
+		{"uneven pattern", []float64{3, 1, 1, 1}, 0, [][2]float64{{0, 3}, {4, 5}, {6, 9}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dashes := dashPolyline(line, false, tt.pattern, tt.offset)
+			if len(dashes) != len(tt.want) {
+				t.Fatalf("got %d dashes %v, want %d", len(dashes), dashes, len(tt.want))
+			}
+			for n, dash := range dashes {
+				start, end := dash[0].X, dash[len(dash)-1].X
+				if math.Abs(start-tt.want[n][0]) > 1e-9 || math.Abs(end-tt.want[n][1]) > 1e-9 {
+					t.Errorf("dash %d spans %v to %v, want %v to %v", n, start, end, tt.want[n][0], tt.want[n][1])
+				}
+			}
+		})
+	}
+}
+
+func TestDashPolylineInvalidPattern(t *testing.T) {
+	line := []Point{Pt(0, 0), Pt(10, 0)}
+	for _, pattern := range [][]float64{{0, 0}, {2, -1}} {
+		dashes := dashPolyline(line, false, pattern, 0)
+		if len(dashes) != 1 || len(dashes[0]) != 2 {
+			t.Errorf("pattern %v: got %v, want the whole line", pattern, dashes)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package imgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// svgPathArgs is the number of arguments of each SVG path command.
+var svgPathArgs = map[byte]int{
+	'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2, 'A': 7, 'Z': 0,
+}
+
+// ParseSVGPath parses the d attribute of an SVG path element, such as "M10 10 h80 v80 h-80 Z".
+func ParseSVGPath(d string) (*Path, error) {
+	p := NewPath()
+	s := &svgPathScanner{src: d}
+
+	var command byte
+	var lastCtrl Point   // last control point, for the smooth curve commands
+	var lastCommand byte // last command in upper case
+	for {
+		s.skipSeparators()
+		if s.done() {
+			break
+		}
+
+		// a command letter, or implicit repetition of the previous command
+		if c := s.src[s.pos]; isSVGPathCommand(c) {
+			command = c
+			s.pos++
+		} else if command == 0 {
+			return nil, fmt.Errorf("%w: expected a command at offset %d", ErrInvalidSVGPath, s.pos)
+		}
+
+		upper := command &^ 0x20
+		relative := command != upper
+		args := make([]float64, svgPathArgs[upper])
+		for n := range args {
+			var err error
+			if upper == 'A' && (n == 3 || n == 4) {
+				args[n], err = s.flag()
+			} else {
+				args[n], err = s.number()
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		cur := p.CurrentPoint()
+		var ox, oy float64
+		if relative {
+			ox, oy = cur.X, cur.Y
+		}
+
+		// the reflection of the last control point, for smooth curves
+		reflected := cur
+		switch upper {
+		case 'S':
+			if lastCommand == 'C' || lastCommand == 'S' {
+				reflected = cur.Mul(2).Sub(lastCtrl)
+			}
+		case 'T':
+			if lastCommand == 'Q' || lastCommand == 'T' {
+				reflected = cur.Mul(2).Sub(lastCtrl)
+			}
+		}
+
+		switch upper {
+		case 'M':
+			p.MoveTo(args[0]+ox, args[1]+oy)
+			// following coordinate pairs are implicit line commands
+			if relative {
+				command = 'l'
+			} else {
+				command = 'L'
+			}
+		case 'L':
+			p.LineTo(args[0]+ox, args[1]+oy)
+		case 'H':
+			p.LineTo(args[0]+ox, cur.Y)
+		case 'V':
+			p.LineTo(cur.X, args[0]+oy)
+		case 'C':
+			lastCtrl = Pt(args[2]+ox, args[3]+oy)
+			p.CubicTo(args[0]+ox, args[1]+oy, lastCtrl.X, lastCtrl.Y, args[4]+ox, args[5]+oy)
+		case 'S':
+			lastCtrl = Pt(args[0]+ox, args[1]+oy)
+			p.CubicTo(reflected.X, reflected.Y, lastCtrl.X, lastCtrl.Y, args[2]+ox, args[3]+oy)
+		case 'Q':
+			lastCtrl = Pt(args[0]+ox, args[1]+oy)
+			p.QuadTo(lastCtrl.X, lastCtrl.Y, args[2]+ox, args[3]+oy)
+		case 'T':
+			lastCtrl = reflected
+			p.QuadTo(lastCtrl.X, lastCtrl.Y, args[0]+ox, args[1]+oy)
+		case 'A':
+			p.ArcTo(args[0], args[1], args[2], args[3] != 0, args[4] != 0, args[5]+ox, args[6]+oy)
+		case 'Z':
+			p.Close()
+			command = 0
+		}
+		lastCommand = upper
+	}
+
+	return p, nil
+}
+
+// isSVGPathCommand reports whether c is an SVG path command letter.
+func isSVGPathCommand(c byte) bool {
+	_, ok := svgPathArgs[c&^0x20]
+	return ok
+}
+
+// svgPathScanner reads the numbers and flags of an SVG path.
+type svgPathScanner struct {
+	src string
+	pos int
+}
+
+// done reports whether the whole path has been read.
+func (s *svgPathScanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+// skipSeparators skips white spaces and commas.
+func (s *svgPathScanner) skipSeparators() {
+	for !s.done() && strings.IndexByte(" \t\r\n,", s.src[s.pos]) >= 0 {
+		s.pos++
+	}
+}
+
+// number reads a number, which may directly follow the previous one, as in "1.5.5" or "1-2".
+func (s *svgPathScanner) number() (float64, error) {
+	s.skipSeparators()
+	start := s.pos
+	if !s.done() && (s.src[s.pos] == '+' || s.src[s.pos] == '-') {
+		s.pos++
+	}
+	digits, dot := 0, false
+	for !s.done() {
+		c := s.src[s.pos]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !dot {
+			dot = true
+		} else {
+			break
+		}
+		s.pos++
+	}
+	if digits > 0 && !s.done() && (s.src[s.pos] == 'e' || s.src[s.pos] == 'E') {
+		s.pos++
+		if !s.done() && (s.src[s.pos] == '+' || s.src[s.pos] == '-') {
+			s.pos++
+		}
+		for !s.done() && s.src[s.pos] >= '0' && s.src[s.pos] <= '9' {
+			s.pos++
+		}
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("%w: expected a number at offset %d", ErrInvalidSVGPath, start)
+	}
+
+	value, err := strconv.ParseFloat(s.src[start:s.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidSVGPath, err)
+	}
+	return value, nil
+}
+
+// flag reads an arc flag, which is a single 0 or 1 that needs no separator.
+func (s *svgPathScanner) flag() (float64, error) {
+	s.skipSeparators()
+	if s.done() || (s.src[s.pos] != '0' && s.src[s.pos] != '1') {
+		return 0, fmt.Errorf("%w: expected a flag at offset %d", ErrInvalidSVGPath, s.pos)
+	}
+	s.pos++
+	return float64(s.src[s.pos-1] - '0'), nil
+}
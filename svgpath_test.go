@@ -0,0 +1,59 @@
+package imgo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSVGPathErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+	}{
+		{"no command", "10 10"},
+		{"missing argument", "M10"},
+		{"not a number", "M10 x"},
+		{"unknown command", "M0 0 X10 10"},
+		{"invalid arc flag", "M0 0 A1 1 0 2 0 5 5"},
+		{"incomplete exponent", "M0 0 L1e 2"},
+		{"lone sign", "M- 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseSVGPath(tt.d)
+			if !errors.Is(err, ErrInvalidSVGPath) {
+				t.Errorf("ParseSVGPath(%q) error = %v, want ErrInvalidSVGPath", tt.d, err)
+			}
+			if p != nil {
+				t.Errorf("ParseSVGPath(%q) returned a path with an error", tt.d)
+			}
+		})
+	}
+}
+
+func TestParseSVGPath(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+		end  Point
+	}{
+		{"absolute and relative lines", "M10 10 h80 v80 h-80", Pt(10, 90)},
+		{"numbers without separators", "M1-2L3.5.5", Pt(3.5, 0.5)},
+		{"implicit line after move", "m1 1 2 2 3 3", Pt(6, 6)},
+		{"arc flags without separators", "M0 0 a5 5 0 1010 0", Pt(10, 0)},
+		{"exponents", "M1e1 2E-1", Pt(10, 0.2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseSVGPath(tt.d)
+			if err != nil {
+				t.Fatalf("ParseSVGPath(%q) error = %v", tt.d, err)
+			}
+			if got := p.CurrentPoint(); got != tt.end {
+				t.Errorf("ParseSVGPath(%q) ends at %v, want %v", tt.d, got, tt.end)
+			}
+		})
+	}
+}
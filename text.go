@@ -0,0 +1,324 @@
+package imgo
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// TextOptions is the options of TextBox and MeasureText, and the effects of Text.
+type TextOptions struct {
+	FontPath      string        // path of the TTF/OTF font file, or name of a font in DefaultFontRegistry
+	FontSize      float64       // font size in points
+	DPI           float64       // screen resolution, 0 is treated as 72
+	Color         color.Color   // text color, nil is treated as black
+	Align         TextAlign     // horizontal alignment of each line
+	VerticalAlign VerticalAlign // vertical alignment of the text block in the box
+	LineHeight    float64       // line height as a multiple of the font line height, 0 is treated as 1
+	Wrap          bool          // wrap words that don't fit in the box width
+	Ellipsis      bool          // truncate overflowing text with an ellipsis instead of clipping it
+	MaxLines      int           // maximum number of lines, 0 means no limit
+
+	LetterSpacing float64         // extra space between two characters, in pixels
+	Fill          image.Image     // image or gradient the glyphs are filled with, in image coordinates, overrides Color
+	StrokeWidth   float64         // width of the outline around the glyphs, in pixels, 0 means no outline
+	StrokeColor   color.Color     // outline color, nil is treated as black
+	Shadow        *Shadow         // drop shadow of the text, nil means no shadow
+	Background    *TextBackground // box drawn behind the text, nil means no box
+}
+
+// TextBackground is the box drawn behind a text.
+type TextBackground struct {
+	Color   color.Color // box color, nil is treated as black
+	Padding int         // space between the text and the edges of the box, in pixels
+	Radius  float64     // radius of the rounded corners of the box, in pixels
+}
+
+// textLine is a laid out line of text.
+type textLine struct {
+	text    string
+	width   fixed.Int26_6
+	justify bool // whether the line can be justified, false for the last line of a paragraph
+}
+
+// textLayout is the result of laying out a text in a box.
+type textLayout struct {
+	lines      []textLine
+	ascent     fixed.Int26_6
+	descent    fixed.Int26_6
+	lineHeight fixed.Int26_6
+	spacing    fixed.Int26_6 // letter spacing
+}
+
+// width returns the width of the widest line.
+func (l textLayout) width() fixed.Int26_6 {
+	var w fixed.Int26_6
+	for _, line := range l.lines {
+		if line.width > w {
+			w = line.width
+		}
+	}
+	return w
+}
+
+// height returns the height of the text block.
+func (l textLayout) height() fixed.Int26_6 {
+	if len(l.lines) == 0 {
+		return 0
+	}
+	return l.ascent + l.descent + l.lineHeight*fixed.Int26_6(len(l.lines)-1)
+}
+
+// TextBox writes a text string into the given rectangle, with word wrapping, alignment and
+// ellipsis truncation. Line breaks in label start new paragraphs.
+func (i *Image) TextBox(label string, rect image.Rectangle, options TextOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	if rect.Empty() {
+		return i
+	}
+
+	face, err := DefaultFontRegistry.Face(options.FontPath, options.FontSize, options.DPI)
+	if err != nil {
+		i.addError(err)
+		return i
+	}
+
+	layout := layoutText(face, label, rect.Dx(), rect.Dy(), options)
+
+	// vertical alignment of the whole text block
+	top := fixed.I(rect.Min.Y)
+	switch options.VerticalAlign {
+	case AlignMiddle:
+		top += (fixed.I(rect.Dy()) - layout.height()) / 2
+	case AlignBottom:
+		top += fixed.I(rect.Dy()) - layout.height()
+	}
+
+	// draw the glyphs into a mask first, so that effects can be built from their shape
+	// drawing into the mask of the box clips the text to the box
+	mask := image.NewAlpha(rect)
+	var textBounds image.Rectangle
+	boxWidth := fixed.I(rect.Dx())
+	for n, line := range layout.lines {
+		baseline := top + layout.ascent + layout.lineHeight*fixed.Int26_6(n)
+		lineTop := (baseline - layout.ascent).Floor()
+		lineBottom := (baseline + layout.descent).Ceil()
+
+		if options.Align == AlignJustify && line.justify {
+			drawJustifiedLine(mask, face, line.text, fixed.I(rect.Min.X), baseline, boxWidth, layout.spacing)
+			textBounds = textBounds.Union(image.Rect(rect.Min.X, lineTop, rect.Max.X, lineBottom))
+			continue
+		}
+
+		x := fixed.I(rect.Min.X)
+		switch options.Align {
+		case AlignCenter:
+			x += (boxWidth - line.width) / 2
+		case AlignRight:
+			x += boxWidth - line.width
+		}
+
+		drawString(mask, image.Opaque, face, fixed.Point26_6{X: x, Y: baseline}, line.text, layout.spacing)
+		textBounds = textBounds.Union(image.Rect(x.Floor(), lineTop, (x + line.width).Ceil(), lineBottom))
+	}
+
+	i.drawText(mask, textBounds, options)
+
+	return i
+}
+
+// MeasureText returns the width and height of a text string laid out with given options.
+// maxWidth is the width of the box the text is wrapped in, 0 means no wrapping.
+func MeasureText(label string, maxWidth int, options TextOptions) (width, height int, err error) {
+	face, err := DefaultFontRegistry.Face(options.FontPath, options.FontSize, options.DPI)
+	if err != nil {
+		return
+	}
+
+	layout := layoutText(face, label, maxWidth, 0, options)
+	return layout.width().Ceil(), layout.height().Ceil(), nil
+}
+
+// layoutText breaks a text string into lines that fit in a box of given width and height.
+// A width or height of 0 means the box is unbounded in that direction.
+func layoutText(face font.Face, label string, width, height int, options TextOptions) textLayout {
+	metrics := face.Metrics()
+	layout := textLayout{
+		ascent:     metrics.Ascent,
+		descent:    metrics.Descent,
+		lineHeight: metrics.Height,
+		spacing:    fixed.Int26_6(options.LetterSpacing * 64),
+	}
+	if options.LineHeight > 0 {
+		layout.lineHeight = fixed.Int26_6(float64(metrics.Height) * options.LineHeight)
+	}
+
+	maxWidth := fixed.I(width)
+	wrap := options.Wrap && width > 0
+
+	for _, paragraph := range strings.Split(label, "\n") {
+		if !wrap {
+			layout.lines = append(layout.lines, textLine{text: paragraph, width: measureString(face, paragraph, layout.spacing)})
+			continue
+		}
+		layout.lines = append(layout.lines, wrapParagraph(face, paragraph, maxWidth, layout.spacing)...)
+	}
+
+	// the number of lines that fit in the box
+	maxLines := options.MaxLines
+	if height > 0 && layout.lineHeight > 0 {
+		fit := 1 + int((fixed.I(height)-layout.ascent-layout.descent)/layout.lineHeight)
+		if fit < 1 {
+			fit = 1
+		}
+		if maxLines <= 0 || fit < maxLines {
+			maxLines = fit
+		}
+	}
+
+	truncated := false
+	if maxLines > 0 && len(layout.lines) > maxLines {
+		layout.lines = layout.lines[:maxLines]
+		truncated = true
+	}
+
+	if options.Ellipsis {
+		for n := range layout.lines {
+			line := &layout.lines[n]
+			last := n == len(layout.lines)-1
+			if (width > 0 && line.width > maxWidth) || (last && truncated) {
+				line.text = ellipsize(face, line.text, maxWidth, last && truncated, layout.spacing)
+				line.width = measureString(face, line.text, layout.spacing)
+				line.justify = false
+			}
+		}
+	}
+
+	return layout
+}
+
+// wrapParagraph breaks a paragraph into lines not wider than maxWidth.
+// Words that are wider than maxWidth on their own are broken between characters.
+func wrapParagraph(face font.Face, paragraph string, maxWidth, spacing fixed.Int26_6) (lines []textLine) {
+	words := strings.Fields(paragraph)
+	if len(words) == 0 {
+		return []textLine{{}}
+	}
+
+	current := ""
+	for _, word := range words {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if measureString(face, candidate, spacing) <= maxWidth {
+			current = candidate
+			continue
+		}
+
+		if current != "" {
+			lines = append(lines, textLine{text: current, width: measureString(face, current, spacing), justify: true})
+		}
+
+		// break the word if it doesn't fit on a line of its own
+		current = ""
+		for _, r := range word {
+			candidate = current + string(r)
+			if current != "" && measureString(face, candidate, spacing) > maxWidth {
+				lines = append(lines, textLine{text: current, width: measureString(face, current, spacing)})
+				candidate = string(r)
+			}
+			current = candidate
+		}
+	}
+	lines = append(lines, textLine{text: current, width: measureString(face, current, spacing)})
+
+	return lines
+}
+
+// ellipsize shortens text until it fits in maxWidth together with an ellipsis.
+// If force is true, the ellipsis is appended even if the text already fits.
+func ellipsize(face font.Face, text string, maxWidth fixed.Int26_6, force bool, spacing fixed.Int26_6) string {
+	ellipsis := "…"
+	if !hasGlyph(face, '…') {
+		ellipsis = "..."
+	}
+
+	if !force && measureString(face, text, spacing) <= maxWidth {
+		return text
+	}
+
+	runes := []rune(strings.TrimRight(text, " "))
+	for len(runes) > 0 && maxWidth > 0 && measureString(face, string(runes)+ellipsis, spacing) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimRight(string(runes), " ") + ellipsis
+}
+
+// hasGlyph reports whether the face has a glyph for r.
+func hasGlyph(face font.Face, r rune) bool {
+	if f, ok := face.(*registryFace); ok {
+		return f.hasGlyph(r)
+	}
+	_, ok := face.GlyphAdvance(r)
+	return ok
+}
+
+// drawJustifiedLine draws a line of text stretched to width by widening the spaces between words.
+func drawJustifiedLine(dst draw.Image, face font.Face, text string, x, baseline, width, spacing fixed.Int26_6) {
+	words := strings.Fields(text)
+	dot := fixed.Point26_6{X: x, Y: baseline}
+	if len(words) < 2 {
+		drawString(dst, image.Opaque, face, dot, text, spacing)
+		return
+	}
+
+	var wordsWidth fixed.Int26_6
+	for _, word := range words {
+		wordsWidth += measureString(face, word, spacing)
+	}
+	gap := (width - wordsWidth) / fixed.Int26_6(len(words)-1)
+
+	for _, word := range words {
+		dot.X += drawString(dst, image.Opaque, face, dot, word, spacing) + gap
+	}
+}
+
+// measureString returns the advance width of s, with spacing added between two characters.
+func measureString(face font.Face, s string, spacing fixed.Int26_6) (advance fixed.Int26_6) {
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 {
+			advance += face.Kern(prev, r) + spacing
+		}
+		a, _ := face.GlyphAdvance(r)
+		advance += a
+		prev = r
+	}
+	return
+}
+
+// drawString draws s with its baseline origin at dot, with spacing added between two characters,
+// and returns its advance width.
+func drawString(dst draw.Image, src image.Image, face font.Face, dot fixed.Point26_6, s string, spacing fixed.Int26_6) fixed.Int26_6 {
+	start := dot.X
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 {
+			dot.X += face.Kern(prev, r) + spacing
+		}
+		dr, mask, maskp, advance, ok := face.Glyph(dot, r)
+		if ok {
+			draw.DrawMask(dst, dr, src, dr.Min, mask, maskp, draw.Over)
+		}
+		dot.X += advance
+		prev = r
+	}
+	return dot.X - start
+}
@@ -0,0 +1,113 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// drawText composites the glyph mask of a text onto the image with the effects of the options,
+// from back to front: background box, shadow, outline and fill.
+// bounds is the bounding box of the laid out text, the background box is drawn around it.
+func (i *Image) drawText(mask *image.Alpha, bounds image.Rectangle, options TextOptions) {
+	if bg := options.Background; bg != nil && !bounds.Empty() {
+		box := roundedBoxMask(bounds.Inset(-bg.Padding), bg.Radius)
+		draw.DrawMask(i.image, box.Bounds(), image.NewUniform(colorOrBlack(bg.Color)), box.Bounds().Min, box, box.Bounds().Min, draw.Over)
+	}
+
+	// the outline is the glyph mask grown by the stroke width, the shadow follows its shape
+	shape := mask
+	if options.StrokeWidth > 0 {
+		shape = dilateAlpha(mask, options.StrokeWidth)
+	}
+
+	if options.Shadow != nil {
+		i.drawShadow(shape, *options.Shadow)
+	}
+
+	if options.StrokeWidth > 0 {
+		draw.DrawMask(i.image, shape.Bounds(), image.NewUniform(colorOrBlack(options.StrokeColor)), shape.Bounds().Min, shape, shape.Bounds().Min, draw.Over)
+	}
+
+	// fill sources are sampled in image coordinates, so that a gradient lines up with the image
+	var src image.Image = image.NewUniform(colorOrBlack(options.Color))
+	if options.Fill != nil {
+		src = options.Fill
+	}
+	draw.DrawMask(i.image, mask.Bounds(), src, mask.Bounds().Min, mask, mask.Bounds().Min, draw.Over)
+}
+
+// dilateAlpha grows the opaque area of mask by width pixels in every direction,
+// with anti-aliased round edges.
+func dilateAlpha(mask *image.Alpha, width float64) *image.Alpha {
+	reach := int(math.Ceil(width + 0.5))
+	dst := image.NewAlpha(mask.Bounds().Inset(-reach))
+
+	// the weight of each offset in the disk of radius width
+	type offset struct {
+		x, y   int
+		weight float64
+	}
+	var offsets []offset
+	for dy := -reach; dy <= reach; dy++ {
+		for dx := -reach; dx <= reach; dx++ {
+			weight := width + 0.5 - math.Hypot(float64(dx), float64(dy))
+			if weight > 0 {
+				offsets = append(offsets, offset{dx, dy, math.Min(weight, 1)})
+			}
+		}
+	}
+
+	// stamp the disk around every covered pixel, keeping the maximum coverage
+	b := mask.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			a := mask.Pix[mask.PixOffset(x, y)]
+			if a == 0 {
+				continue
+			}
+			for _, o := range offsets {
+				value := uint8(float64(a) * o.weight)
+				p := dst.PixOffset(x+o.x, y+o.y)
+				if value > dst.Pix[p] {
+					dst.Pix[p] = value
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+// roundedBoxMask returns an anti-aliased mask of rect with rounded corners.
+func roundedBoxMask(rect image.Rectangle, radius float64) *image.Alpha {
+	mask := image.NewAlpha(rect)
+	radius = math.Max(0, math.Min(radius, math.Min(float64(rect.Dx()), float64(rect.Dy()))/2))
+
+	// the centers of the corner circles span this rectangle
+	minX, minY := float64(rect.Min.X)+radius, float64(rect.Min.Y)+radius
+	maxX, maxY := float64(rect.Max.X)-radius, float64(rect.Max.Y)-radius
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			cx, cy := math.Max(minX, math.Min(px, maxX)), math.Max(minY, math.Min(py, maxY))
+			coverage := 1.0
+			if radius > 0 {
+				coverage = math.Max(0, math.Min(1, radius-math.Hypot(px-cx, py-cy)+0.5))
+			}
+			mask.Pix[mask.PixOffset(x, y)] = uint8(coverage * 255)
+		}
+	}
+
+	return mask
+}
+
+// colorOrBlack returns c, or black if c is nil.
+func colorOrBlack(c color.Color) color.Color {
+	if c == nil {
+		return color.Black
+	}
+	return c
+}
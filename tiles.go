@@ -0,0 +1,249 @@
+package imgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// TileOptions is the options of SaveTiles.
+type TileOptions struct {
+	Layout     TileLayout  // folder layout of the pyramid, default is TileDeepZoom
+	TileSize   int         // width and height of the tiles, without the overlap, default is 256
+	Overlap    int         // pixels shared with the neighbouring tiles on each side, Deep Zoom only
+	Format     string      // png, jpg, jpeg, tiff or bmp, default is png for XYZ and jpg otherwise
+	Quality    int         // quality of jpeg tiles, default is 100
+	Name       string      // name of the .dzi file and the tiles folder of Deep Zoom, default is "image"
+	ID         string      // id of the image in the info.json of IIIF, the URL the folder is served at, required for IIIF
+	Background color.Color // color of the partial tiles on the right and bottom edges of XYZ, default is transparent, or white for jpeg
+}
+
+// tileJob is a tile of a pyramid level to encode.
+type tileJob struct {
+	level               *Image
+	x, y, width, height int
+	size                int // size of the square tile the tile is padded to, or 0
+	path                string
+}
+
+// Tiles splits the image into tiles of width x height, row by row. The tiles of the last
+// column and row are smaller if the size of the image is not a multiple of the tile size.
+func (i Image) Tiles(width, height int) [][]*Image {
+	if i.Error != nil || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	var tiles [][]*Image
+	for y := 0; y < i.height; y += height {
+		var row []*Image
+		for x := 0; x < i.width; x += width {
+			tile := i
+			if tile.Crop(x, y, width, height).image == i.image {
+				tile = *i.Clone()
+			}
+			row = append(row, &tile)
+		}
+		tiles = append(tiles, row)
+	}
+	return tiles
+}
+
+// SaveTiles saves the image to dir as a pyramid of tiles for zoomable viewers, from the full
+// resolution down to a single tile, each level half the size of the previous one.
+// With TileDeepZoom it writes name.dzi and name_files/level/column_row.format, with TileIIIF a
+// level 0 IIIF image service, info.json and region/size/0/default.format, where the region is
+// full for the levels of a single tile, listed as sizes in info.json, and with TileXYZ
+// zoom/column/row.format, with full square tiles. Tiles are encoded concurrently.
+func (i *Image) SaveTiles(dir string, options ...TileOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	var opt TileOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if opt.TileSize <= 0 {
+		opt.TileSize = 256
+	}
+	if opt.Overlap < 0 || opt.Layout != TileDeepZoom {
+		opt.Overlap = 0
+	}
+	if opt.Format == "" {
+		opt.Format = "jpg"
+		if opt.Layout == TileXYZ {
+			opt.Format = "png"
+		}
+	}
+	opt.Format = strings.ToLower(opt.Format)
+	if !(opt.Format == "png" || opt.Format == "jpg" || opt.Format == "jpeg" || opt.Format == "tiff" || opt.Format == "bmp") {
+		i.addError(ErrSaveImageFormatNotSupport)
+		return i
+	}
+	// jpeg has no alpha, transparent padding would turn black
+	if opt.Background == nil && (opt.Format == "jpg" || opt.Format == "jpeg") {
+		opt.Background = color.White
+	}
+	if opt.Name == "" {
+		opt.Name = "image"
+	}
+	if opt.Layout == TileIIIF && opt.ID == "" {
+		i.addError(ErrTileIDRequired)
+		return i
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		i.addError(err)
+		return i
+	}
+
+	// Deep Zoom goes down to 1 x 1 pixel, IIIF and XYZ to a single tile
+	smallest := opt.TileSize
+	if opt.Layout == TileDeepZoom {
+		smallest = 1
+	}
+	levels := []*Image{i}
+	for level := i; level.width > smallest || level.height > smallest; {
+		next := *level
+		level = next.Resize((level.width+1)/2, (level.height+1)/2)
+		if level.Error != nil {
+			i.addError(level.Error, true)
+			return i
+		}
+		levels = append(levels, level)
+	}
+
+	var jobs []tileJob
+	var scaleFactors []int
+	var sizes []map[string]int
+	for n, level := range levels {
+		for row := 0; row*opt.TileSize < level.height; row++ {
+			for col := 0; col*opt.TileSize < level.width; col++ {
+				job := tileJob{
+					level:  level,
+					x:      col*opt.TileSize - opt.Overlap,
+					y:      row*opt.TileSize - opt.Overlap,
+					width:  opt.TileSize + 2*opt.Overlap,
+					height: opt.TileSize + 2*opt.Overlap,
+				}
+				switch opt.Layout {
+				case TileIIIF:
+					// the region in full resolution pixels, and the size of the tile
+					scale := 1 << n
+					x, y := col*opt.TileSize*scale, row*opt.TileSize*scale
+					w, h := opt.TileSize*scale, opt.TileSize*scale
+					if x+w > i.width {
+						w = i.width - x
+					}
+					if y+h > i.height {
+						h = i.height - y
+					}
+					tw, th := (w+scale-1)/scale, (h+scale-1)/scale
+					region := fmt.Sprintf("%d,%d,%d,%d", x, y, w, h)
+					// the levels of a single tile are scaled full images, which viewers request as sizes
+					if w == i.width && h == i.height {
+						region = "full"
+						sizes = append([]map[string]int{{"width": tw, "height": th}}, sizes...)
+					}
+					job.path = filepath.Join(dir, region, fmt.Sprintf("%d,%d", tw, th), "0", "default."+opt.Format)
+				case TileXYZ:
+					job.size = opt.TileSize
+					job.path = filepath.Join(dir, fmt.Sprint(len(levels)-1-n), fmt.Sprint(col), fmt.Sprint(row)+"."+opt.Format)
+				default:
+					job.path = filepath.Join(dir, opt.Name+"_files", fmt.Sprint(len(levels)-1-n), fmt.Sprintf("%d_%d.%s", col, row, opt.Format))
+				}
+				jobs = append(jobs, job)
+			}
+		}
+		scaleFactors = append(scaleFactors, 1<<n)
+	}
+
+	// encode the tiles concurrently, and keep the first error
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	queue := make(chan tileJob)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if err := job.save(opt); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		i.addError(firstErr, true)
+		return i
+	}
+
+	// the description of the pyramid
+	var err error
+	switch opt.Layout {
+	case TileIIIF:
+		info := map[string]interface{}{
+			"@context": "http://iiif.io/api/image/3/context.json",
+			"id":       opt.ID,
+			"type":     "ImageService3",
+			"protocol": "http://iiif.io/api/image",
+			"profile":  "level0",
+			"width":    i.width,
+			"height":   i.height,
+			"tiles": []map[string]interface{}{
+				{"width": opt.TileSize, "height": opt.TileSize, "scaleFactors": scaleFactors},
+			},
+			"sizes": sizes,
+		}
+		var data []byte
+		if data, err = json.MarshalIndent(info, "", "  "); err == nil {
+			err = os.WriteFile(filepath.Join(dir, "info.json"), data, 0644)
+		}
+	case TileDeepZoom:
+		dzi := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Image xmlns="http://schemas.microsoft.com/deepzoom/2008" Format="%s" Overlap="%d" TileSize="%d">
+  <Size Width="%d" Height="%d"/>
+</Image>
+`, opt.Format, opt.Overlap, opt.TileSize, i.width, i.height)
+		err = os.WriteFile(filepath.Join(dir, opt.Name+".dzi"), []byte(dzi), 0644)
+	}
+	if err != nil {
+		i.addError(err)
+	}
+
+	return i
+}
+
+// save crops the tile from its level, pads it if needed, and saves it.
+func (job tileJob) save(opt TileOptions) error {
+	if err := os.MkdirAll(filepath.Dir(job.path), 0755); err != nil {
+		return err
+	}
+
+	tile := *job.level
+	tile.Crop(job.x, job.y, job.width, job.height)
+	if job.size > 0 && (tile.width < job.size || tile.height < job.size) {
+		background := opt.Background
+		if background == nil {
+			background = color.Transparent
+		}
+		tile = *Canvas(job.size, job.size, background).Insert(&tile, 0, 0)
+	}
+
+	return tile.Save(job.path, opt.Quality).Error
+}
@@ -0,0 +1,156 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Quad is a quadrilateral, with its corners in the order top-left, top-right, bottom-right and bottom-left.
+type Quad [4]Point
+
+// RectQuad returns the quad of the rectangle at (x, y) with given width and height.
+func RectQuad(x, y, width, height float64) Quad {
+	return Quad{Pt(x, y), Pt(x+width, y), Pt(x+width, y+height), Pt(x, y+height)}
+}
+
+// TransformOptions is the options of Affine and Perspective.
+type TransformOptions struct {
+	Interpolation Interpolation // how pixels are interpolated, default is InterpolationBilinear
+	Background    color.Color   // color of the area not covered by the image, default is transparent
+	Width         int           // width of the result, default fits the transformed image
+	Height        int           // height of the result, default fits the transformed image
+}
+
+// Affine transforms the image by the matrix m. The result keeps the coordinates of the transform,
+// so by default it spans from (0, 0) to the bottom-right corner of the transformed image, and the
+// parts moved to negative coordinates are cut off.
+func (i *Image) Affine(m Matrix, options ...TransformOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	inverse, ok := m.Invert()
+	if !ok {
+		i.addError(ErrMatrixNotInvertible)
+		return i
+	}
+
+	var corners Quad
+	for n, p := range RectQuad(0, 0, float64(i.width), float64(i.height)) {
+		corners[n] = m.Apply(p)
+	}
+
+	return i.transform(corners, options, func(p Point) (Point, bool) {
+		return inverse.Apply(p), true
+	})
+}
+
+// Perspective warps the image so that the quad src of the image lands on the quad dst, with the
+// homography between them. It can straighten a photographed document, with src its corners on the
+// photo and dst a rectangle, or map artwork onto a mockup, with src the corners of the artwork.
+// The result spans from (0, 0) to the bottom-right corner of dst by default.
+func (i *Image) Perspective(src, dst Quad, options ...TransformOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	// pixels of the result are mapped back to the image with the homography from dst to src
+	h, ok := homography(dst, src)
+	if !ok {
+		i.addError(ErrDegenerateQuad)
+		return i
+	}
+
+	// points on the other side of the horizon than the quad have no source
+	center := dst[0].Add(dst[1]).Add(dst[2]).Add(dst[3]).Mul(0.25)
+	side := math.Copysign(1, h[6]*center.X+h[7]*center.Y+h[8])
+
+	return i.transform(dst, options, func(p Point) (Point, bool) {
+		w := h[6]*p.X + h[7]*p.Y + h[8]
+		if w*side <= 0 {
+			return Point{}, false
+		}
+		return Pt((h[0]*p.X+h[1]*p.Y+h[2])/w, (h[3]*p.X+h[4]*p.Y+h[5])/w), true
+	})
+}
+
+// transform warps the image into a result large enough for the quad corners, or of the size of the options.
+func (i *Image) transform(corners Quad, options []TransformOptions, inverse func(p Point) (Point, bool)) *Image {
+	var opt TransformOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	w, h := opt.Width, opt.Height
+	if w <= 0 || h <= 0 {
+		var maxX, maxY float64
+		for _, p := range corners {
+			maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+		}
+		if w <= 0 {
+			w = int(math.Ceil(maxX - 1e-6))
+		}
+		if h <= 0 {
+			h = int(math.Ceil(maxY - 1e-6))
+		}
+	}
+	if w <= 0 || h <= 0 {
+		i.addError(ErrEmptyTransform)
+		return i
+	}
+
+	origin := i.image.Bounds().Min
+	offset := Pt(float64(origin.X), float64(origin.Y))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	warp(dst, i.image, opt.Interpolation, opt.Background, func(p Point) (Point, bool) {
+		q, ok := inverse(p)
+		return q.Add(offset), ok
+	})
+
+	i.image = dst
+	i.width = w
+	i.height = h
+	return i
+}
+
+// homography returns the 3 x 3 matrix, row by row, of the projective transform that maps the
+// corners of from to the corners of to, and false if the quads are degenerate.
+func homography(from, to Quad) (h [9]float64, ok bool) {
+	// the 8 equations of the unknowns h0 to h7, with h8 = 1
+	var a [8][9]float64
+	for n := 0; n < 4; n++ {
+		x, y, u, v := from[n].X, from[n].Y, to[n].X, to[n].Y
+		a[2*n] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*n+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return h, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			f := a[row][col] / a[col][col]
+			for k := col; k < 9; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+		}
+	}
+
+	for n := 0; n < 8; n++ {
+		h[n] = a[n][8] / a[n][n]
+	}
+	h[8] = 1
+	return h, true
+}
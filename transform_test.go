@@ -0,0 +1,118 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// applyHomography maps p with the homography h.
+func applyHomography(h [9]float64, p Point) Point {
+	w := h[6]*p.X + h[7]*p.Y + h[8]
+	return Pt((h[0]*p.X+h[1]*p.Y+h[2])/w, (h[3]*p.X+h[4]*p.Y+h[5])/w)
+}
+
+func TestHomographyCorners(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Quad
+	}{
+		{"identity", RectQuad(0, 0, 100, 80), RectQuad(0, 0, 100, 80)},
+		{"translation and scale", RectQuad(0, 0, 100, 80), RectQuad(20, 10, 50, 160)},
+		{"trapezoid", RectQuad(0, 0, 100, 100), Quad{Pt(30, 0), Pt(70, 0), Pt(100, 100), Pt(0, 100)}},
+		{"photographed document", Quad{Pt(12, 30), Pt(180, 8), Pt(205, 260), Pt(3, 240)}, RectQuad(0, 0, 210, 297)},
+		{"rotated", RectQuad(0, 0, 40, 20), Quad{Pt(20, 0), Pt(40, 20), Pt(20, 40), Pt(0, 20)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, ok := homography(tt.from, tt.to)
+			if !ok {
+				t.Fatal("homography is degenerate")
+			}
+			for n, p := range tt.from {
+				if got := applyHomography(h, p); math.Abs(got.X-tt.to[n].X) > 1e-6 || math.Abs(got.Y-tt.to[n].Y) > 1e-6 {
+					t.Errorf("corner %d maps to %v, want %v", n, got, tt.to[n])
+				}
+			}
+
+			// the inverse homography maps the corners back
+			inverse, ok := homography(tt.to, tt.from)
+			if !ok {
+				t.Fatal("inverse homography is degenerate")
+			}
+			for n, p := range tt.from {
+				if got := applyHomography(inverse, applyHomography(h, p)); math.Abs(got.X-p.X) > 1e-6 || math.Abs(got.Y-p.Y) > 1e-6 {
+					t.Errorf("corner %d round trips to %v, want %v", n, got, p)
+				}
+			}
+		})
+	}
+}
+
+func TestHomographyDegenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Quad
+	}{
+		{"collinear corners", Quad{Pt(0, 0), Pt(50, 0), Pt(100, 0), Pt(0, 100)}, RectQuad(0, 0, 100, 100)},
+		{"repeated corner", Quad{Pt(0, 0), Pt(0, 0), Pt(100, 100), Pt(0, 100)}, RectQuad(0, 0, 100, 100)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := homography(tt.from, tt.to); ok {
+				t.Error("homography is not degenerate")
+			}
+		})
+	}
+}
+
+func TestPerspectiveRoundTrip(t *testing.T) {
+	// an image with a different color in each quarter
+	colors := [4]color.RGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}, {255, 255, 0, 255}}
+	img := Canvas(100, 100)
+	for n, rect := range []image.Rectangle{image.Rect(0, 0, 50, 50), image.Rect(50, 0, 100, 50), image.Rect(50, 50, 100, 100), image.Rect(0, 50, 50, 100)} {
+		img.FillRect(rect, colors[n])
+	}
+
+	tests := []struct {
+		name string
+		dst  Quad
+	}{
+		{"trapezoid", Quad{Pt(40, 10), Pt(110, 10), Pt(150, 120), Pt(0, 120)}},
+		{"skewed", Quad{Pt(10, 30), Pt(120, 0), Pt(140, 90), Pt(30, 140)}},
+		{"scaled", RectQuad(20, 20, 60, 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := RectQuad(0, 0, 100, 100)
+			warped := img.Clone().Perspective(src, tt.dst, TransformOptions{Interpolation: InterpolationNearest})
+			if warped.Error != nil {
+				t.Fatal(warped.Error)
+			}
+
+			// the corners of dst have the colors of the corners of the image, checked a little inside
+			center := tt.dst[0].Add(tt.dst[1]).Add(tt.dst[2]).Add(tt.dst[3]).Mul(0.25)
+			for n, corner := range tt.dst {
+				p := corner.Add(center.Sub(corner).Mul(0.1))
+				if got := warped.image.RGBAAt(int(p.X), int(p.Y)); got != colors[n] {
+					t.Errorf("corner %d of the warped image is %v, want %v", n, got, colors[n])
+				}
+			}
+
+			back := warped.Perspective(tt.dst, src, TransformOptions{Interpolation: InterpolationNearest, Width: 100, Height: 100})
+			if back.Error != nil {
+				t.Fatal(back.Error)
+			}
+			for n, corner := range src {
+				p := corner.Add(Pt(50, 50).Sub(corner).Mul(0.1))
+				if got := back.image.RGBAAt(int(p.X), int(p.Y)); got != colors[n] {
+					t.Errorf("corner %d of the round trip is %v, want %v", n, got, colors[n])
+				}
+			}
+		})
+	}
+}
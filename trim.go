@@ -0,0 +1,98 @@
+package imgo
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// TrimOptions is the options of Trim.
+type TrimOptions struct {
+	Color       color.Color // background color to trim, default is the color of the top-left pixel
+	Transparent bool        // trim transparent pixels instead of a color, default when the top-left pixel is transparent
+	Padding     int         // margin of background kept around the content
+}
+
+// Trim crops the image to its content, removing the uniform borders around it, such as white
+// margins of scans or transparent margins of exported images.
+// tolerance is the largest difference, from 0 to 255, of any RGBA channel with the background
+// color, or the largest alpha of transparent pixels. The image is unchanged if it has no content.
+func (i *Image) Trim(tolerance int, options ...TrimOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	rect := i.TrimRect(tolerance, options...)
+	if rect.Empty() || rect == i.image.Bounds() {
+		return i
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), i.image, rect.Min, draw.Src)
+	i.image = dst
+	i.width = rect.Dx()
+	i.height = rect.Dy()
+
+	return i
+}
+
+// TrimRect returns the rectangle Trim would crop the image to, without changing the image.
+// It returns an empty rectangle if the image has no content.
+func (i Image) TrimRect(tolerance int, options ...TrimOptions) image.Rectangle {
+	if i.Error != nil {
+		return image.Rectangle{}
+	}
+
+	var opt TrimOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	img := i.image
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return image.Rectangle{}
+	}
+
+	// the background color, from the options or the top-left pixel
+	var background [4]int
+	if opt.Color != nil {
+		c := color.RGBAModel.Convert(opt.Color).(color.RGBA)
+		background = [4]int{int(c.R), int(c.G), int(c.B), int(c.A)}
+	} else {
+		p := img.Pix[img.PixOffset(bounds.Min.X, bounds.Min.Y):]
+		background = [4]int{int(p[0]), int(p[1]), int(p[2]), int(p[3])}
+		if background[3] == 0 {
+			opt.Transparent = true
+		}
+	}
+
+	isBackground := func(p []uint8) bool {
+		if opt.Transparent {
+			return int(p[3]) <= tolerance
+		}
+		for c := 0; c < 4; c++ {
+			d := int(p[c]) - background[c]
+			if d > tolerance || -d > tolerance {
+				return false
+			}
+		}
+		return true
+	}
+
+	// the bounding box of the pixels that are not background
+	content := image.Rectangle{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		offset := img.PixOffset(bounds.Min.X, y)
+		for x := bounds.Min.X; x < bounds.Max.X; x, offset = x+1, offset+4 {
+			if !isBackground(img.Pix[offset : offset+4]) {
+				content = content.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if content.Empty() {
+		return image.Rectangle{}
+	}
+
+	return content.Inset(-opt.Padding).Intersect(bounds)
+}
@@ -0,0 +1,135 @@
+package imgo
+
+import (
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// WatermarkOptions is the options of Watermark and TextWatermark.
+type WatermarkOptions struct {
+	SpacingX int     // horizontal gap between two tiles, in pixels
+	SpacingY int     // vertical gap between two tiles, in pixels
+	Angle    float64 // rotation angle of each tile in degrees, clockwise
+	Opacity  float64 // opacity of the watermark between 0 and 1, 0 is treated as 1
+	Stagger  bool    // shift every other row by half a tile, like a brick wall
+}
+
+// Watermark tiles source across the whole image.
+// source can be a file path, a URL, a base64 encoded string, an *os.File, an image.Image,
+// a byte slice or an *Image.
+func (i *Image) Watermark(source interface{}, options ...WatermarkOptions) *Image {
+	// the watermark tile, cloned so that the source is never modified
+	var tile *Image
+	switch source.(type) {
+	case *Image:
+		tile = source.(*Image).Clone()
+	default:
+		tile = Load(source)
+	}
+
+	// check errors
+	if tile.Error != nil {
+		i.addError(tile.Error, true)
+		return i
+	}
+	if i.Error != nil {
+		return i
+	}
+
+	var opt WatermarkOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	return i.tileWatermark(tile, opt)
+}
+
+// TextWatermark tiles a text string across the whole image.
+// fontPath is the path of the font file, or the name of a font registered in DefaultFontRegistry.
+func (i *Image) TextWatermark(label string, fontPath string, fontColor color.Color, fontSize float64, dpi float64, options ...WatermarkOptions) *Image {
+	if i.Error != nil {
+		return i
+	}
+
+	// measure the text to get the size of the tile
+	face, err := DefaultFontRegistry.Face(fontPath, fontSize, dpi)
+	if err != nil {
+		i.addError(err)
+		return i
+	}
+
+	metrics := face.Metrics()
+	ascent := metrics.Ascent.Ceil()
+	width := font.MeasureString(face, label).Ceil()
+	height := ascent + metrics.Descent.Ceil()
+	if width <= 0 || height <= 0 {
+		return i
+	}
+
+	// draw the text with its baseline at the ascent, so that it fits in the tile
+	tile := Canvas(width, height)
+	d := font.Drawer{
+		Dst:  tile.image,
+		Src:  image.NewUniform(fontColor),
+		Face: face,
+		Dot:  fixed.P(0, ascent),
+	}
+	d.DrawString(label)
+
+	var opt WatermarkOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	return i.tileWatermark(tile, opt)
+}
+
+// tileWatermark rotates the tile and draws it repeatedly over the whole image.
+func (i *Image) tileWatermark(tile *Image, opt WatermarkOptions) *Image {
+	if tile.width == 0 || tile.height == 0 {
+		return i
+	}
+
+	if opt.Angle != 0 {
+		tile.RotateAngle(opt.Angle)
+		if tile.Error != nil {
+			i.addError(tile.Error, true)
+			return i
+		}
+	}
+
+	// apply the opacity to the tile once, instead of to every single copy
+	if opt.Opacity > 0 && opt.Opacity < 1 {
+		faded := image.NewRGBA(tile.image.Bounds())
+		mask := image.NewUniform(color.Alpha{A: uint8(math.Round(opt.Opacity * 255))})
+		draw.DrawMask(faded, faded.Bounds(), tile.image, tile.image.Bounds().Min, mask, image.Point{}, draw.Over)
+		tile.image = faded
+	}
+
+	stepX := tile.width + opt.SpacingX
+	stepY := tile.height + opt.SpacingY
+	if stepX <= 0 || stepY <= 0 {
+		return i
+	}
+
+	// Start from the tile that covers (0, 0), one step further to the left when staggered,
+	// so that there is no empty seam on the edges of the image.
+	row := 0
+	for y := 0; y < i.height; y += stepY {
+		startX := 0
+		if opt.Stagger && row%2 == 1 {
+			startX = -stepX / 2
+		}
+		for x := startX; x < i.width; x += stepX {
+			rect := image.Rect(x, y, x+tile.width, y+tile.height)
+			draw.Draw(i.image, rect, tile.image, tile.image.Bounds().Min, draw.Over)
+		}
+		row++
+	}
+
+	return i
+}